@@ -0,0 +1,5 @@
+data "karpor_ai_status" "this" {}
+
+output "ai_enabled" {
+  value = data.karpor_ai_status.this.enabled
+}
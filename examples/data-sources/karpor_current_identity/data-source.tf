@@ -0,0 +1,9 @@
+data "karpor_current_identity" "this" {}
+
+output "authenticated_as" {
+  value = "${data.karpor_current_identity.this.kind} ${data.karpor_current_identity.this.name}"
+}
+
+output "permissions" {
+  value = data.karpor_current_identity.this.permissions
+}
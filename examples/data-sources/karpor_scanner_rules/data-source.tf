@@ -0,0 +1,7 @@
+data "karpor_scanner_rules" "high" {
+  severity = "High"
+}
+
+output "high_severity_rules" {
+  value = data.karpor_scanner_rules.high.ids
+}
@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    karpor = {
+      source  = "registry.terraform.io/KusionStack/karpor"
+      version = "0.1.0"
+    }
+  }
+}
+
+provider "karpor" {
+  api_endpoint    = "https://127.0.0.1:7443"
+  api_key         = "your-api-key-here"
+  skip_tls_verify = true
+}
+
+# The kubeconfig is issued on every run and never stored in plan or state
+ephemeral "karpor_cluster_kubeconfig" "example" {
+  cluster_name       = "local-cluster"
+  expiration_seconds = 1800
+}
+
+provider "kubernetes" {
+  host                   = ephemeral.karpor_cluster_kubeconfig.example.host
+  cluster_ca_certificate = ephemeral.karpor_cluster_kubeconfig.example.cluster_ca_certificate
+  token                  = ephemeral.karpor_cluster_kubeconfig.example.token
+}
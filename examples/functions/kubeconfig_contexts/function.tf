@@ -0,0 +1,8 @@
+# Names of the contexts of a kubeconfig, e.g. to register each with kubeconfig_extract
+locals {
+  kubeconfig = file("~/.kube/config")
+}
+
+output "contexts" {
+  value = provider::karpor::kubeconfig_contexts(local.kubeconfig)
+}
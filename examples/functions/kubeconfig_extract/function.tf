@@ -0,0 +1,10 @@
+locals {
+  kubeconfig = file("~/.kube/config")
+}
+
+resource "karpor_cluster_registration" "clusters" {
+  for_each = toset(provider::karpor::kubeconfig_contexts(local.kubeconfig))
+
+  cluster_name = each.key
+  credentials  = provider::karpor::kubeconfig_extract(local.kubeconfig, each.key)
+}
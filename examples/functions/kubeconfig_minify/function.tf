@@ -0,0 +1,5 @@
+# Only register the current context instead of the whole kubeconfig
+resource "karpor_cluster_registration" "example" {
+  cluster_name = "local-cluster"
+  credentials  = provider::karpor::kubeconfig_minify(file("~/.kube/config"))
+}
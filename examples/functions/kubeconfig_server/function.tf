@@ -0,0 +1,3 @@
+output "api_server" {
+  value = provider::karpor::kubeconfig_server(file("~/.kube/config"))
+}
@@ -0,0 +1,13 @@
+output "production_web_pods" {
+  value = provider::karpor::search_query({
+    clusters   = ["prod-hangzhou", "prod-shanghai"]
+    kinds      = "Pod"
+    namespaces = ["web"]
+    labels = {
+      "app.kubernetes.io/name" = "storefront"
+    }
+    conditions = [
+      { field = "name", operator = "not like", value = "canary-%" },
+    ]
+  })
+}
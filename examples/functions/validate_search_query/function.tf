@@ -0,0 +1,9 @@
+variable "search_query" {
+  type    = string
+  default = "select * from resources where kind = 'Deployment' and namespace = 'default'"
+}
+
+# Fails at plan time with the position of the first syntax error
+output "search_query" {
+  value = provider::karpor::validate_search_query(var.search_query)
+}
@@ -10,4 +10,15 @@ terraform {
 provider "karpor" {
   api_endpoint = "https://api.karpor.example.com"
   api_key      = "your-api-key-here"
+
+  # Fail fast on a wrong endpoint or key instead of during the first apply
+  verify_credentials = true
+
+  # Merged into the labels of every cluster registered by this workspace
+  default_labels {
+    labels = {
+      managed-by = "terraform"
+      team       = "platform"
+    }
+  }
 }
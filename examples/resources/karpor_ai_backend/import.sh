@@ -0,0 +1,2 @@
+# Karpor has a single AI backend, the ID is always ai-backend
+terraform import karpor_ai_backend.this ai-backend
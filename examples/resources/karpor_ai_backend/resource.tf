@@ -0,0 +1,17 @@
+variable "openai_token" {
+  type      = string
+  sensitive = true
+  ephemeral = true
+}
+
+resource "karpor_ai_backend" "this" {
+  backend     = "openai"
+  model       = "gpt-4o-mini"
+  temperature = 0.2
+  proxy_url   = "http://proxy.example.com:3128"
+  no_proxy    = "localhost,127.0.0.1,.svc"
+
+  # Bump the version to rotate the token
+  auth_token_wo      = var.openai_token
+  auth_token_version = 1
+}
@@ -0,0 +1,2 @@
+# Import by token name
+terraform import karpor_api_token.ci ci
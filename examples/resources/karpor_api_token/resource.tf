@@ -0,0 +1,25 @@
+resource "time_rotating" "ci_token" {
+  rotation_days = 30
+}
+
+# Issue a token for CI, rotated every 30 days
+resource "karpor_api_token" "ci" {
+  name     = "ci"
+  role     = "viewer"
+  clusters = ["staging"]
+  ttl      = "1080h"
+
+  rotation_trigger = {
+    rotated_at = time_rotating.ci_token.id
+  }
+}
+
+# Hand the token to the CI secret store through a write-only argument
+resource "vault_kv_secret_v2" "ci_token" {
+  mount = "ci"
+  name  = "karpor"
+  data_json_wo = jsonencode({
+    token = karpor_api_token.ci.token
+  })
+  data_json_wo_version = time_rotating.ci_token.unix
+}
@@ -0,0 +1,2 @@
+# Import by policy name
+terraform import karpor_audit_policy.production production
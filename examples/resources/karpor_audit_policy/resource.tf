@@ -0,0 +1,25 @@
+data "karpor_scanner_rules" "kubeaudit" {
+  scanner = "kubeaudit"
+}
+
+resource "karpor_audit_policy" "production" {
+  name         = "production"
+  clusters     = ["prod-eu", "prod-us"]
+  min_severity = "Medium"
+
+  # Report every kubeaudit finding as critical
+  rules = [
+    for id in data.karpor_scanner_rules.kubeaudit.ids : {
+      id       = id
+      severity = "Critical"
+    }
+  ]
+
+  exemptions = [
+    {
+      labels = { "app.kubernetes.io/part-of" = "istio" }
+      rules  = ["privileged"]
+      reason = "Istio CNI requires privileged containers"
+    },
+  ]
+}
@@ -0,0 +1,8 @@
+# Import by cluster name
+terraform import karpor_cluster_registration.example local-cluster
+
+# Import by the uid assigned by Karpor
+terraform import karpor_cluster_registration.example uid:3f1b2c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d
+
+# Import by cluster name and uid, failing if they do not match
+terraform import karpor_cluster_registration.example local-cluster/3f1b2c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d
@@ -18,16 +18,58 @@ resource "karpor_cluster_registration" "example" {
   display_name = "local-cluster-display-name"
   credentials  = file("~/config")
   description  = "local-cluster-description"
+
+  labels = {
+    env    = "dev"
+    region = "cn-hangzhou"
+  }
+  annotations = {
+    "example.com/owner" = "platform-team"
+  }
+
+  # Refuse to destroy or replace the cluster until this is set to false
+  deletion_protection = true
+
+  # Wait until Karpor has connected and synced the cluster so that
+  # dependent resources can search it
+  wait_for_sync = true
+
+  timeouts {
+    create = "30m"
+  }
 }
 
+# Terraform 1.11 and later: keep the kubeconfig out of plan and state,
+# bump credentials_version to rotate the credentials in place
+resource "karpor_cluster_registration" "write_only" {
+  cluster_name        = "write-only-cluster"
+  credentials_wo      = file("~/config")
+  credentials_version = 1
+}
+
+# Take over a cluster registered outside Terraform, registration fails
+# if it points at another API server than the credentials
+resource "karpor_cluster_registration" "adopted" {
+  cluster_name   = "existing-cluster"
+  credentials    = file("~/config")
+  adopt_existing = true
+}
 
 # make sure you have a existing demo cluster in karpor
-# id is the cluster name
+# id is the cluster name, "uid:<uid>" or "<cluster_name>/<uid>"
 import {
   to = karpor_cluster_registration.imported
   id = "demo"
 }
 
+# Terraform 1.12 and later can import by resource identity instead
+# import {
+#   to       = karpor_cluster_registration.imported
+#   identity = {
+#     id = "3f1b2c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+#   }
+# }
+
 resource "karpor_cluster_registration" "imported" {
   cluster_name = "demo"
   # Setting credentials in imported resource will destroy and then create replacement
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    karpor = {
+      source  = "registry.terraform.io/KusionStack/karpor"
+      version = "0.1.0"
+    }
+  }
+}
+
+provider "karpor" {
+  api_endpoint    = "https://127.0.0.1:7443"
+  api_key         = "your-api-key-here"
+  skip_tls_verify = true
+}
+
+# Register every kubeconfig in a directory, 10 clusters at a time
+resource "karpor_cluster_registrations" "region" {
+  parallelism = 10
+  clusters = {
+    for f in fileset("${path.module}/kubeconfigs", "*.yaml") :
+    trimsuffix(f, ".yaml") => {
+      description    = "Registered from ${f}"
+      credentials_wo = file("${path.module}/kubeconfigs/${f}")
+      # Bump to rotate the credentials of the cluster
+      credentials_version = 1
+    }
+  }
+}
+
+output "cluster_ids" {
+  value = { for name, cluster in karpor_cluster_registrations.region.clusters : name => cluster.id }
+}
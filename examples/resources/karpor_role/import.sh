@@ -0,0 +1,2 @@
+# Import by role name
+terraform import karpor_role.viewer team-viewer
@@ -0,0 +1,11 @@
+resource "karpor_role" "viewer" {
+  name        = "team-viewer"
+  description = "Browse, search and audit clusters"
+
+  rules = [
+    { resource = "cluster", verbs = ["get", "list"] },
+    { resource = "search", verbs = ["get"] },
+    { resource = "insight", verbs = ["get", "list"] },
+    { resource = "resource_group", verbs = ["get", "list"] },
+  ]
+}
@@ -0,0 +1,2 @@
+# Import by role binding name
+terraform import karpor_role_binding.team_a team-a-viewer
@@ -0,0 +1,22 @@
+# Team A may browse its own clusters
+resource "karpor_role_binding" "team_a" {
+  name     = "team-a-viewer"
+  role     = karpor_role.viewer.name
+  clusters = ["team-a-staging", "team-a-prod"]
+
+  subjects = [
+    { kind = "Group", name = "team-a" },
+    { kind = "APIToken", name = karpor_api_token.ci.name },
+  ]
+}
+
+# Platform engineers may browse every resource group of the namespace rule
+resource "karpor_role_binding" "platform" {
+  name                = "platform-viewer"
+  role                = karpor_role.viewer.name
+  resource_group_rule = "namespace"
+
+  subjects = [
+    { kind = "Group", name = "platform" },
+  ]
+}
@@ -0,0 +1,2 @@
+# Import by rule name
+terraform import karpor_sync_resources_rule.production production-workloads
@@ -0,0 +1,25 @@
+# Only index workloads and recent events of the production clusters
+resource "karpor_sync_resources_rule" "production" {
+  name = "production-workloads"
+  cluster_labels = {
+    env = "prod"
+  }
+
+  resources = [
+    {
+      api_version        = "apps/v1"
+      kind               = "Deployment"
+      exclude_namespaces = ["kube-system"]
+    },
+    {
+      api_version    = "v1"
+      kind           = "Pod"
+      field_selector = "status.phase!=Succeeded"
+    },
+    {
+      api_version = "v1"
+      kind        = "Event"
+      max_age     = "72h"
+    },
+  ]
+}
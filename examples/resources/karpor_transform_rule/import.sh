@@ -0,0 +1,2 @@
+# Import by rule name
+terraform import karpor_transform_rule.redact_secret_data redact-secret-data
@@ -0,0 +1,26 @@
+# Never index the content of secrets
+resource "karpor_transform_rule" "redact_secret_data" {
+  name           = "redact-secret-data"
+  type           = "remove"
+  value_template = "$.data"
+}
+
+# Drop bulky metadata before indexing
+resource "karpor_transform_rule" "trim_metadata" {
+  name           = "trim-metadata"
+  type           = "patch"
+  value_template = <<-EOT
+    {"metadata": {"managedFields": null, "annotations": {"kubectl.kubernetes.io/last-applied-configuration": null}}}
+  EOT
+}
+
+resource "karpor_sync_resources_rule" "secrets" {
+  name = "secrets"
+  resources = [
+    {
+      api_version    = "v1"
+      kind           = "Secret"
+      transform_rule = karpor_transform_rule.redact_secret_data.name
+    },
+  ]
+}
@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// aiBackendID is the ID of the AI backend, Karpor has a single one.
+	aiBackendID = "ai-backend"
+
+	aiBackendOpenAI      = "openai"
+	aiBackendAzureOpenAI = "azureopenai"
+	aiBackendHuggingFace = "huggingface"
+)
+
+var (
+	// httpURLPattern matches HTTP and HTTPS URLs.
+	httpURLPattern = regexp.MustCompile(`^https?://[^\s]+$`)
+	// proxyURLPattern matches the URLs of HTTP, HTTPS and SOCKS5 proxies.
+	proxyURLPattern = regexp.MustCompile(`^(https?|socks5)://[^\s]+$`)
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &AIBackendResource{}
+	_ resource.ResourceWithConfigure      = &AIBackendResource{}
+	_ resource.ResourceWithImportState    = &AIBackendResource{}
+	_ resource.ResourceWithValidateConfig = &AIBackendResource{}
+)
+
+// NewAIBackendResource returns a new resource.Resource.
+func NewAIBackendResource() resource.Resource {
+	return &AIBackendResource{}
+}
+
+// AIBackendResource is the resource implementation.
+type AIBackendResource struct {
+	client *KarporClient
+}
+
+// AIBackendResourceModel is the resource model.
+type AIBackendResourceModel struct {
+	Backend          types.String  `tfsdk:"backend"`
+	BaseURL          types.String  `tfsdk:"base_url"`
+	Model            types.String  `tfsdk:"model"`
+	Temperature      types.Float64 `tfsdk:"temperature"`
+	TopP             types.Float64 `tfsdk:"top_p"`
+	ProxyURL         types.String  `tfsdk:"proxy_url"`
+	NoProxy          types.String  `tfsdk:"no_proxy"`
+	AuthTokenWo      types.String  `tfsdk:"auth_token_wo"`
+	AuthTokenVersion types.Int64   `tfsdk:"auth_token_version"`
+	Id               types.String  `tfsdk:"id"`
+}
+
+// Metadata returns the resource type name.
+func (r *AIBackendResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ai_backend"
+}
+
+// Schema returns the resource schema.
+func (r *AIBackendResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage the LLM backend of Karpor's natural-language search and AI diagnosis. " +
+			"Karpor has a single backend, destroying the resource disables the AI features",
+		Attributes: map[string]schema.Attribute{
+			"backend": schema.StringAttribute{
+				Required:    true,
+				Description: "Type of the backend, one of `openai`, `azureopenai` or `huggingface`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(aiBackendOpenAI, aiBackendAzureOpenAI, aiBackendHuggingFace),
+				},
+			},
+			"base_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "Base URL of the backend API, required for `azureopenai`",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpURLPattern, "must be an HTTP or HTTPS URL"),
+				},
+			},
+			"model": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Model to use, defaults to the backend's default model",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"temperature": schema.Float64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Sampling temperature between 0 and 2",
+				Validators: []validator.Float64{
+					float64validator.Between(0, 2),
+				},
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"top_p": schema.Float64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Nucleus sampling probability between 0 and 1",
+				Validators: []validator.Float64{
+					float64validator.Between(0, 1),
+				},
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL of the proxy Karpor reaches the backend through",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(proxyURLPattern, "must be an HTTP, HTTPS or SOCKS5 URL"),
+				},
+			},
+			"no_proxy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comma-separated hosts reached without the proxy",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("proxy_url")),
+				},
+			},
+			"auth_token_wo": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Description: "Token authenticating Karpor to the backend, never stored in plan or state. Requires Terraform 1.11 or later",
+			},
+			"auth_token_version": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Version of `auth_token_wo`, change it to rotate the token",
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("auth_token_wo")),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier, always `" + aiBackendID + "`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks the settings required by the backend type.
+func (r *AIBackendResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config AIBackendResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Backend.ValueString() == aiBackendAzureOpenAI && config.BaseURL.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("base_url"),
+			"Missing Base URL",
+			"The azureopenai backend requires base_url to be set to the endpoint of the Azure OpenAI resource.",
+		)
+	}
+}
+
+// Create creates the resource.
+func (r *AIBackendResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AIBackendResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only tokens are only available in the configuration
+	var authTokenWo types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("auth_token_wo"), &authTokenWo)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	backend, err := r.client.UpdateAIBackend(ctx, plan.backend(authTokenWo.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure AI backend", err.Error())
+		return
+	}
+	tflog.Info(ctx, "Configured AI backend", map[string]interface{}{"backend": backend.Backend})
+
+	plan.setRemote(backend)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read reads the resource.
+func (r *AIBackendResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AIBackendResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	backend, err := r.client.GetAIBackend(ctx)
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "AI backend removed outside of Terraform")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Karpor AI Backend",
+			"Could not read Karpor AI backend: "+err.Error(),
+		)
+		return
+	}
+
+	state.setRemote(backend)
+	state.BaseURL = stringOrNull(backend.BaseURL)
+	state.ProxyURL = stringOrNull(backend.ProxyURL)
+	state.NoProxy = stringOrNull(backend.NoProxy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource.
+func (r *AIBackendResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state AIBackendResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var authTokenWo types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("auth_token_wo"), &authTokenWo)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Send the token only when its version changes, Karpor keeps the
+	// current token otherwise
+	authToken := ""
+	if !plan.AuthTokenVersion.Equal(state.AuthTokenVersion) {
+		authToken = authTokenWo.ValueString()
+	}
+
+	backend, err := r.client.UpdateAIBackend(ctx, plan.backend(authToken))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update AI backend", err.Error())
+		return
+	}
+
+	plan.setRemote(backend)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource.
+func (r *AIBackendResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	err := r.client.DeleteAIBackend(ctx)
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Karpor AI Backend",
+			"Could not delete AI backend, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// ImportState imports the AI backend, the import ID is ignored.
+func (r *AIBackendResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), aiBackendID)...)
+}
+
+// Configure configures the resource.
+func (r *AIBackendResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// backend converts the model into the configuration sent to Karpor.
+func (m *AIBackendResourceModel) backend(authToken string) *AIBackend {
+	return &AIBackend{
+		Backend:     m.Backend.ValueString(),
+		BaseURL:     m.BaseURL.ValueString(),
+		Model:       m.Model.ValueString(),
+		Temperature: knownFloat64Pointer(m.Temperature),
+		TopP:        knownFloat64Pointer(m.TopP),
+		ProxyURL:    m.ProxyURL.ValueString(),
+		NoProxy:     m.NoProxy.ValueString(),
+		AuthToken:   authToken,
+	}
+}
+
+// setRemote sets the attributes Karpor may default from its configuration.
+func (m *AIBackendResourceModel) setRemote(backend *AIBackend) {
+	m.Backend = types.StringValue(backend.Backend)
+	m.Model = types.StringValue(backend.Model)
+	m.Temperature = types.Float64PointerValue(backend.Temperature)
+	m.TopP = types.Float64PointerValue(backend.TopP)
+	m.Id = types.StringValue(aiBackendID)
+}
+
+// knownFloat64Pointer returns the value of a number, or nil when it is null
+// or unknown so that Karpor applies its default.
+func knownFloat64Pointer(v types.Float64) *float64 {
+	if v.IsUnknown() {
+		return nil
+	}
+	return v.ValueFloat64Pointer()
+}
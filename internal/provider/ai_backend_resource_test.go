@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccAIBackend(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			// Azure OpenAI requires a base URL
+			{
+				Config: providerConfig + `
+				resource "karpor_ai_backend" "test" {
+					backend = "azureopenai"
+				}
+				`,
+				ExpectError: regexp.MustCompile(`Missing Base URL`),
+			},
+			// Create and Read
+			{
+				Config: providerConfig + `
+				resource "karpor_ai_backend" "test" {
+					backend            = "openai"
+					model              = "gpt-4o-mini"
+					temperature        = 0.2
+					auth_token_wo      = "test-token"
+					auth_token_version = 1
+				}
+
+				data "karpor_ai_status" "test" {
+					depends_on = [karpor_ai_backend.test]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_ai_backend.test",
+						tfjsonpath.New("auth_token_wo"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_ai_backend.test",
+						tfjsonpath.New("top_p"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"data.karpor_ai_status.test",
+						tfjsonpath.New("enabled"),
+						knownvalue.Bool(true),
+					),
+					statecheck.ExpectKnownValue(
+						"data.karpor_ai_status.test",
+						tfjsonpath.New("model"),
+						knownvalue.StringExact("gpt-4o-mini"),
+					),
+				},
+			},
+			// Update and Read
+			{
+				Config: providerConfig + `
+				resource "karpor_ai_backend" "test" {
+					backend            = "openai"
+					model              = "gpt-4o"
+					temperature        = 0.5
+					proxy_url          = "http://proxy.example.com:3128"
+					no_proxy           = "localhost,127.0.0.1"
+					auth_token_wo      = "rotated-token"
+					auth_token_version = 2
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_ai_backend.test",
+						tfjsonpath.New("temperature"),
+						knownvalue.Float64Exact(0.5),
+					),
+				},
+			},
+			// Import
+			{
+				ResourceName:            "karpor_ai_backend.test",
+				ImportState:             true,
+				ImportStateId:           aiBackendID,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"auth_token_version"},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
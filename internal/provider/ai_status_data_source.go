@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &AIStatusDataSource{}
+	_ datasource.DataSourceWithConfigure = &AIStatusDataSource{}
+)
+
+// NewAIStatusDataSource returns a new datasource.DataSource.
+func NewAIStatusDataSource() datasource.DataSource {
+	return &AIStatusDataSource{}
+}
+
+// AIStatusDataSource is the datasource implementation.
+type AIStatusDataSource struct {
+	client *KarporClient
+}
+
+// AIStatusDataSourceModel is the datasource model.
+type AIStatusDataSourceModel struct {
+	Enabled types.Bool   `tfsdk:"enabled"`
+	Backend types.String `tfsdk:"backend"`
+	Model   types.String `tfsdk:"model"`
+}
+
+// Metadata returns the metadata for the datasource.
+func (d *AIStatusDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ai_status"
+}
+
+// Schema returns the schema for the datasource.
+func (d *AIStatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Get whether the AI features of Karpor are enabled",
+		Attributes: map[string]schema.Attribute{
+			"enabled": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether natural-language search and AI diagnosis are available",
+			},
+			"backend": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of the configured backend, null when AI is disabled",
+			},
+			"model": schema.StringAttribute{
+				Computed:    true,
+				Description: "Model of the configured backend, null when AI is disabled",
+			},
+		},
+	}
+}
+
+// Read reads the datasource.
+func (d *AIStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	status, err := d.client.GetAIStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get AI status", err.Error())
+		return
+	}
+
+	state := AIStatusDataSourceModel{
+		Enabled: types.BoolValue(status.Enabled),
+		Backend: stringOrNull(status.Backend),
+		Model:   stringOrNull(status.Model),
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Configure configures the datasource.
+func (d *AIStatusDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
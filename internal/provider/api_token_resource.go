@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &APITokenResource{}
+	_ resource.ResourceWithConfigure   = &APITokenResource{}
+	_ resource.ResourceWithImportState = &APITokenResource{}
+)
+
+// NewAPITokenResource returns a new resource.Resource.
+func NewAPITokenResource() resource.Resource {
+	return &APITokenResource{}
+}
+
+// APITokenResource is the resource implementation.
+type APITokenResource struct {
+	client *KarporClient
+}
+
+// APITokenResourceModel is the resource model. The token is signed once, when
+// it is issued, and kept in state from then on.
+type APITokenResourceModel struct {
+	Name            types.String `tfsdk:"name"`
+	Role            types.String `tfsdk:"role"`
+	Clusters        types.List   `tfsdk:"clusters"`
+	TTL             types.String `tfsdk:"ttl"`
+	RotationTrigger types.Map    `tfsdk:"rotation_trigger"`
+	CreatedAt       types.String `tfsdk:"created_at"`
+	ExpiresAt       types.String `tfsdk:"expires_at"`
+	Id              types.String `tfsdk:"id"`
+	Token           types.String `tfsdk:"token"`
+}
+
+// Metadata returns the resource type name.
+func (r *APITokenResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_token"
+}
+
+// Schema returns the resource schema.
+func (r *APITokenResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Issue a Karpor access token, revoked on destroy. The token is signed once when it is issued and stored in state as a sensitive value, " +
+			"hand it to a write-only argument to keep it out of other resources. Change `rotation_trigger` to revoke it and issue a new one",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Unique name for the token",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the `karpor_role` granted to the token",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"clusters": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Names of the clusters the token is restricted to, by default the role applies to all clusters",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: "Lifetime of the token, e.g. `720h`, by default the token does not expire",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(durationPattern, "must be a duration such as \"720h\" or \"1h30m\""),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"rotation_trigger": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values, changing them revokes the token and issues a new one",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Time the token was issued, in RFC3339 format",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Time the token expires, in RFC3339 format, null when it does not expire",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier of the token",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Bearer token authenticating to Karpor, signed once when the token is issued. Null for imported tokens, which cannot be signed again",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource.
+func (r *APITokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan APITokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token, err := r.client.CreateAPIToken(ctx, plan.Name.ValueString(), &APITokenSpec{
+		Role:     plan.Role.ValueString(),
+		Clusters: stringListValue(plan.Clusters),
+		TTL:      plan.TTL.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to issue API token", err.Error())
+		return
+	}
+	tflog.Info(ctx, "Issued API token", map[string]interface{}{"name": token.Metadata.Name, "expires_at": token.Status.ExpiresAt})
+
+	plan.setRemote(token)
+	plan.Token = types.StringNull()
+
+	// Sign the token once, it is never signed again for this resource
+	secret, err := r.client.GetAPITokenSecret(ctx, plan.Name.ValueString())
+	if err != nil {
+		// Keep the issued token in state, it is tainted and revoked on the next apply
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.AddError("Failed to sign API token", err.Error())
+		return
+	}
+	plan.Token = types.StringValue(secret.Token)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read reads the resource.
+func (r *APITokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state APITokenResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token, err := r.client.GetAPIToken(ctx, state.Name.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "API token revoked outside of Terraform", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Karpor API Token",
+			"Could not read Karpor API token "+state.Name.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(token.Metadata.Name)
+	state.Role = types.StringValue(token.Spec.Role)
+	state.Clusters = stringListOrNull(token.Spec.Clusters)
+	state.TTL = stringOrNull(token.Spec.TTL)
+	state.setRemote(token)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource. Every argument requires replacement, so there
+// is nothing to send to Karpor.
+func (r *APITokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan APITokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete revokes the token.
+func (r *APITokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state APITokenResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteAPIToken(ctx, state.Name.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Revoking Karpor API Token",
+			"Could not revoke API token, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource by name.
+func (r *APITokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
+
+// Configure configures the resource.
+func (r *APITokenResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// setRemote sets the attributes computed by Karpor.
+func (m *APITokenResourceModel) setRemote(token *APIToken) {
+	m.Id = types.StringValue(token.Metadata.UID)
+	m.CreatedAt = stringOrNull(token.Status.CreatedAt)
+	m.ExpiresAt = stringOrNull(token.Status.ExpiresAt)
+}
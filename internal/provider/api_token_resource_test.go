@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccAPIToken(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read
+			{
+				Config: providerConfig + `
+				resource "karpor_api_token" "test" {
+					name             = "test-api-token"
+					role             = "viewer"
+					ttl              = "24h"
+					rotation_trigger = { version = "1" }
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_api_token.test",
+						tfjsonpath.New("expires_at"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_api_token.test",
+						tfjsonpath.New("token"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Rotate
+			{
+				Config: providerConfig + `
+				resource "karpor_api_token" "test" {
+					name             = "test-api-token"
+					role             = "viewer"
+					ttl              = "24h"
+					rotation_trigger = { version = "2" }
+				}
+				`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("karpor_api_token.test", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+			// Import
+			{
+				ResourceName:                         "karpor_api_token.test",
+				ImportState:                          true,
+				ImportStateId:                        "test-api-token",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+				ImportStateVerifyIgnore:              []string{"rotation_trigger", "token"},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAPITokenSignedOnce(t *testing.T) {
+	ctx := context.Background()
+	var signed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/secret") {
+			signed++
+			_, _ = w.Write([]byte(`{"success": true, "data": {"token": "secret-token"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success": true, "data": {"metadata": {"name": "ci", "uid": "uid-ci"}, "spec": {"role": "viewer"}}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &APITokenResource{client: client}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["name"] = tftypes.NewValue(tftypes.String, "ci")
+	values["role"] = tftypes.NewValue(tftypes.String, "viewer")
+	raw := tftypes.NewValue(objectType, values)
+
+	createResp := &fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	r.Create(ctx, fwresource.CreateRequest{
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: raw},
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: raw},
+	}, createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatal(createResp.Diagnostics)
+	}
+
+	// Reading the token keeps the token signed at creation
+	readResp := &fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatal(readResp.Diagnostics)
+	}
+	var state APITokenResourceModel
+	readResp.Diagnostics.Append(readResp.State.Get(ctx, &state)...)
+	if readResp.Diagnostics.HasError() {
+		t.Fatal(readResp.Diagnostics)
+	}
+	if state.Token.ValueString() != "secret-token" {
+		t.Errorf("expected the token signed at creation, got %s", state.Token)
+	}
+	if signed != 1 {
+		t.Errorf("expected the token to be signed once, got %d", signed)
+	}
+}
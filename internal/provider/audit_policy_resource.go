@@ -0,0 +1,417 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// auditSeverities are the severities of scanner issues, from lowest to highest.
+var auditSeverities = []string{"Low", "Medium", "High", "Critical"}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &AuditPolicyResource{}
+	_ resource.ResourceWithConfigure      = &AuditPolicyResource{}
+	_ resource.ResourceWithImportState    = &AuditPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &AuditPolicyResource{}
+)
+
+// NewAuditPolicyResource returns a new resource.Resource.
+func NewAuditPolicyResource() resource.Resource {
+	return &AuditPolicyResource{}
+}
+
+// AuditPolicyResource is the resource implementation.
+type AuditPolicyResource struct {
+	client *KarporClient
+}
+
+// AuditPolicyResourceModel is the resource model.
+type AuditPolicyResourceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Clusters    types.List   `tfsdk:"clusters"`
+	Namespaces  types.List   `tfsdk:"namespaces"`
+	MinSeverity types.String `tfsdk:"min_severity"`
+	Rules       types.List   `tfsdk:"rules"`
+	Exemptions  types.List   `tfsdk:"exemptions"`
+	Id          types.String `tfsdk:"id"`
+}
+
+// AuditPolicyRuleModel is the model of a scanner rule override.
+type AuditPolicyRuleModel struct {
+	Id       types.String `tfsdk:"id"`
+	Enabled  types.Bool   `tfsdk:"enabled"`
+	Severity types.String `tfsdk:"severity"`
+}
+
+// AuditPolicyExemptionModel is the model of an exemption by labels.
+type AuditPolicyExemptionModel struct {
+	Labels types.Map    `tfsdk:"labels"`
+	Rules  types.List   `tfsdk:"rules"`
+	Reason types.String `tfsdk:"reason"`
+}
+
+// auditPolicyRuleAttrTypes are the attribute types of AuditPolicyRuleModel.
+var auditPolicyRuleAttrTypes = map[string]attr.Type{
+	"id":       types.StringType,
+	"enabled":  types.BoolType,
+	"severity": types.StringType,
+}
+
+// auditPolicyExemptionAttrTypes are the attribute types of AuditPolicyExemptionModel.
+var auditPolicyExemptionAttrTypes = map[string]attr.Type{
+	"labels": types.MapType{ElemType: types.StringType},
+	"rules":  types.ListType{ElemType: types.StringType},
+	"reason": types.StringType,
+}
+
+// Metadata returns the resource type name.
+func (r *AuditPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_audit_policy"
+}
+
+// Schema returns the resource schema.
+func (r *AuditPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage the scanner checks and severities behind Karpor insight scores",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Unique name for the policy",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"clusters": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Names of the clusters the policy applies to, by default it applies to all clusters",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"namespaces": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Namespaces the policy applies to, by default it applies to all namespaces",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"min_severity": schema.StringAttribute{
+				Optional:    true,
+				Description: "Lowest severity of the reported issues, one of `Low`, `Medium`, `High` or `Critical`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(auditSeverities...),
+				},
+			},
+			"rules": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Overrides of built-in scanner rules setting `enabled`, `severity` or both, see the `karpor_scanner_rules` data source for their IDs",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "ID of the scanner rule",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"enabled": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Whether the rule is checked, by default the rule keeps its built-in state",
+						},
+						"severity": schema.StringAttribute{
+							Optional:    true,
+							Description: "Severity of the issues reported by the rule, one of `Low`, `Medium`, `High` or `Critical`",
+							Validators: []validator.String{
+								stringvalidator.OneOf(auditSeverities...),
+							},
+						},
+					},
+				},
+			},
+			"exemptions": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Objects exempted from the checks by label",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"labels": schema.MapAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							Description: "Labels the exempted objects carry, all of them must match",
+							Validators: []validator.Map{
+								mapvalidator.SizeAtLeast(1),
+							},
+						},
+						"rules": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "IDs of the scanner rules the objects are exempted from, by default all rules",
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+							},
+						},
+						"reason": schema.StringAttribute{
+							Optional:    true,
+							Description: "Why the objects are exempted",
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that each scanner rule is overridden once and that
+// each override sets enabled or severity.
+func (r *AuditPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config AuditPolicyResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Rules.IsUnknown() {
+		return
+	}
+
+	var rules []AuditPolicyRuleModel
+	resp.Diagnostics.Append(config.Rules.ElementsAs(ctx, &rules, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if rule.Enabled.IsNull() && rule.Severity.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rules").AtListIndex(i),
+				"Empty Scanner Rule Override",
+				fmt.Sprintf("The override of scanner rule %q sets neither enabled nor severity, set at least one of them or remove the entry.", rule.Id.ValueString()),
+			)
+		}
+		if rule.Id.IsNull() || rule.Id.IsUnknown() {
+			continue
+		}
+		id := rule.Id.ValueString()
+		if seen[id] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rules").AtListIndex(i).AtName("id"),
+				"Duplicate Scanner Rule",
+				fmt.Sprintf("The scanner rule %q is overridden more than once, merge its overrides into a single entry.", id),
+			)
+		}
+		seen[id] = true
+	}
+}
+
+// Create creates the resource.
+func (r *AuditPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AuditPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, diags := plan.spec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.CreateAuditPolicy(ctx, plan.Name.ValueString(), spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create audit policy", err.Error())
+		return
+	}
+	tflog.Info(ctx, "Created audit policy", map[string]interface{}{"name": policy.Metadata.Name})
+
+	plan.Id = types.StringValue(policy.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read reads the resource.
+func (r *AuditPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AuditPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.GetAuditPolicy(ctx, state.Name.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "Audit policy removed outside of Terraform", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Karpor Audit Policy",
+			"Could not read Karpor audit policy "+state.Name.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(state.setRemote(ctx, policy)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource.
+func (r *AuditPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan AuditPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, diags := plan.spec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.UpdateAuditPolicy(ctx, plan.Name.ValueString(), spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update audit policy", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(policy.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource.
+func (r *AuditPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AuditPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteAuditPolicy(ctx, state.Name.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Karpor Audit Policy",
+			"Could not delete audit policy, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource by name.
+func (r *AuditPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
+
+// Configure configures the resource.
+func (r *AuditPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// spec converts the model into the policy spec sent to Karpor.
+func (m *AuditPolicyResourceModel) spec(ctx context.Context) (*AuditPolicySpec, diag.Diagnostics) {
+	var rules []AuditPolicyRuleModel
+	var exemptions []AuditPolicyExemptionModel
+	diags := m.Rules.ElementsAs(ctx, &rules, false)
+	diags.Append(m.Exemptions.ElementsAs(ctx, &exemptions, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	spec := &AuditPolicySpec{
+		Clusters:    stringListValue(m.Clusters),
+		Namespaces:  stringListValue(m.Namespaces),
+		MinSeverity: m.MinSeverity.ValueString(),
+	}
+	for _, rule := range rules {
+		spec.Rules = append(spec.Rules, AuditRuleOverride{
+			ID:       rule.Id.ValueString(),
+			Enabled:  rule.Enabled.ValueBoolPointer(),
+			Severity: rule.Severity.ValueString(),
+		})
+	}
+	for _, exemption := range exemptions {
+		spec.Exemptions = append(spec.Exemptions, AuditExemption{
+			Labels: stringMapValue(exemption.Labels),
+			Rules:  stringListValue(exemption.Rules),
+			Reason: exemption.Reason.ValueString(),
+		})
+	}
+	return spec, diags
+}
+
+// setRemote overwrites the model with the policy read from Karpor.
+func (m *AuditPolicyResourceModel) setRemote(ctx context.Context, policy *AuditPolicy) diag.Diagnostics {
+	m.Name = types.StringValue(policy.Metadata.Name)
+	m.Id = types.StringValue(policy.Metadata.UID)
+	m.Clusters = stringListOrNull(policy.Spec.Clusters)
+	m.Namespaces = stringListOrNull(policy.Spec.Namespaces)
+	m.MinSeverity = stringOrNull(policy.Spec.MinSeverity)
+
+	var diags diag.Diagnostics
+	m.Rules = types.ListNull(types.ObjectType{AttrTypes: auditPolicyRuleAttrTypes})
+	if len(policy.Spec.Rules) > 0 {
+		rules := make([]AuditPolicyRuleModel, 0, len(policy.Spec.Rules))
+		for _, rule := range policy.Spec.Rules {
+			rules = append(rules, AuditPolicyRuleModel{
+				Id:       types.StringValue(rule.ID),
+				Enabled:  types.BoolPointerValue(rule.Enabled),
+				Severity: stringOrNull(rule.Severity),
+			})
+		}
+		m.Rules, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: auditPolicyRuleAttrTypes}, rules)
+	}
+
+	m.Exemptions = types.ListNull(types.ObjectType{AttrTypes: auditPolicyExemptionAttrTypes})
+	if len(policy.Spec.Exemptions) > 0 {
+		exemptions := make([]AuditPolicyExemptionModel, 0, len(policy.Spec.Exemptions))
+		for _, exemption := range policy.Spec.Exemptions {
+			exemptions = append(exemptions, AuditPolicyExemptionModel{
+				Labels: stringMapOrNull(exemption.Labels),
+				Rules:  stringListOrNull(exemption.Rules),
+				Reason: stringOrNull(exemption.Reason),
+			})
+		}
+		var exemptionDiags diag.Diagnostics
+		m.Exemptions, exemptionDiags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: auditPolicyExemptionAttrTypes}, exemptions)
+		diags.Append(exemptionDiags...)
+	}
+	return diags
+}
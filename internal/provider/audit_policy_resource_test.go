@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccAuditPolicy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Rules are overridden once
+			{
+				Config: providerConfig + `
+				resource "karpor_audit_policy" "test" {
+					name = "test-audit-policy"
+					rules = [
+						{ id = "privileged", enabled = false },
+						{ id = "privileged", severity = "Low" },
+					]
+				}
+				`,
+				ExpectError: regexp.MustCompile(`Duplicate Scanner Rule`),
+			},
+			// Rule overrides set enabled or severity
+			{
+				Config: providerConfig + `
+				resource "karpor_audit_policy" "test" {
+					name  = "test-audit-policy"
+					rules = [{ id = "privileged" }]
+				}
+				`,
+				ExpectError: regexp.MustCompile(`Empty Scanner Rule Override`),
+			},
+			// Create and Read
+			{
+				Config: providerConfig + `
+				data "karpor_scanner_rules" "all" {}
+
+				resource "karpor_audit_policy" "test" {
+					name         = "test-audit-policy"
+					clusters     = ["demo"]
+					min_severity = "Medium"
+					rules = [
+						{
+							id      = data.karpor_scanner_rules.all.ids[0]
+							enabled = false
+						},
+					]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.karpor_scanner_rules.all",
+						tfjsonpath.New("rules"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_audit_policy.test",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Update
+			{
+				Config: providerConfig + `
+				data "karpor_scanner_rules" "all" {}
+
+				resource "karpor_audit_policy" "test" {
+					name       = "test-audit-policy"
+					clusters   = ["demo"]
+					namespaces = ["default"]
+					rules = [
+						{
+							id       = data.karpor_scanner_rules.all.ids[0]
+							severity = "Critical"
+						},
+					]
+					exemptions = [
+						{
+							labels = { "karpor.io/audit-exempt" = "true" }
+							reason = "Vendor workloads"
+						},
+					]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_audit_policy.test",
+						tfjsonpath.New("rules").AtSliceIndex(0).AtMapKey("severity"),
+						knownvalue.StringExact("Critical"),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_audit_policy.test",
+						tfjsonpath.New("exemptions").AtSliceIndex(0).AtMapKey("labels").AtMapKey("karpor.io/audit-exempt"),
+						knownvalue.StringExact("true"),
+					),
+				},
+			},
+			// Import
+			{
+				ResourceName:                         "karpor_audit_policy.test",
+				ImportState:                          true,
+				ImportStateId:                        "test-audit-policy",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
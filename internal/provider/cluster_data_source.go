@@ -31,6 +31,8 @@ type ClusterDataSourceModel struct {
 	DisplayName types.String `tfsdk:"display_name"`
 	Description types.String `tfsdk:"description"`
 	Id          types.String `tfsdk:"id"`
+	Labels      types.Map    `tfsdk:"labels"`
+	Annotations types.Map    `tfsdk:"annotations"`
 }
 
 // Metadata returns the metadata for the datasource.
@@ -55,6 +57,14 @@ func (d *ClusterDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
+			"labels": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"annotations": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
 		},
 	}
 }
@@ -79,6 +89,8 @@ func (d *ClusterDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		DisplayName: types.StringValue(cluster.DisplayName.ValueString()),
 		Description: types.StringValue(cluster.Description.ValueString()),
 		Id:          types.StringValue(cluster.Id.ValueString()),
+		Labels:      cluster.Labels,
+		Annotations: cluster.Annotations,
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultKubeConfigExpirationSeconds is the lifetime of an issued kubeconfig
+// when expiration_seconds is not set.
+const defaultKubeConfigExpirationSeconds = 3600
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &ClusterKubeConfigEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &ClusterKubeConfigEphemeralResource{}
+)
+
+// NewClusterKubeConfigEphemeralResource returns a new ephemeral.EphemeralResource.
+func NewClusterKubeConfigEphemeralResource() ephemeral.EphemeralResource {
+	return &ClusterKubeConfigEphemeralResource{}
+}
+
+// ClusterKubeConfigEphemeralResource is the ephemeral resource implementation.
+type ClusterKubeConfigEphemeralResource struct {
+	client *KarporClient
+}
+
+// ClusterKubeConfigEphemeralResourceModel is the ephemeral resource model.
+type ClusterKubeConfigEphemeralResourceModel struct {
+	ClusterName          types.String `tfsdk:"cluster_name"`
+	ExpirationSeconds    types.Int64  `tfsdk:"expiration_seconds"`
+	KubeConfigRaw        types.String `tfsdk:"kubeconfig_raw"`
+	Host                 types.String `tfsdk:"host"`
+	ClusterCACertificate types.String `tfsdk:"cluster_ca_certificate"`
+	Token                types.String `tfsdk:"token"`
+	ExpiresAt            types.String `tfsdk:"expires_at"`
+}
+
+// Metadata returns the ephemeral resource type name.
+func (e *ClusterKubeConfigEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_kubeconfig"
+}
+
+// Schema returns the ephemeral resource schema.
+func (e *ClusterKubeConfigEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Issue a short-lived kubeconfig for a cluster registered in Karpor, never stored in plan or state",
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the registered cluster",
+			},
+			"expiration_seconds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Requested lifetime of the issued credentials in seconds, by default it is 3600",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(600),
+				},
+			},
+			"kubeconfig_raw": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Kubeconfig content reaching the cluster through Karpor",
+			},
+			"host": schema.StringAttribute{
+				Computed:    true,
+				Description: "Address of the cluster API server proxied by Karpor",
+			},
+			"cluster_ca_certificate": schema.StringAttribute{
+				Computed:    true,
+				Description: "PEM-encoded CA certificate of the proxied API server",
+			},
+			"token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Bearer token for the proxied API server",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Expiration timestamp of the issued credentials",
+			},
+		},
+	}
+}
+
+// Open issues the kubeconfig.
+func (e *ClusterKubeConfigEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ClusterKubeConfigEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	expirationSeconds := int64(defaultKubeConfigExpirationSeconds)
+	if !data.ExpirationSeconds.IsNull() {
+		expirationSeconds = data.ExpirationSeconds.ValueInt64()
+	}
+
+	kubeConfig, err := e.client.GetClusterKubeConfig(ctx, data.ClusterName.ValueString(), expirationSeconds)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to issue cluster kubeconfig",
+			"Could not issue kubeconfig for Karpor cluster "+data.ClusterName.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	caCertificate, err := base64.StdEncoding.DecodeString(kubeConfig.CertificateAuthorityData)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid certificate authority data", err.Error())
+		return
+	}
+	tflog.Debug(ctx, "Issued cluster kubeconfig", map[string]interface{}{
+		"cluster_name": data.ClusterName.ValueString(),
+		"expires_at":   kubeConfig.ExpirationTimestamp,
+	})
+
+	data.ExpirationSeconds = types.Int64Value(expirationSeconds)
+	data.KubeConfigRaw = types.StringValue(kubeConfig.KubeConfig)
+	data.Host = types.StringValue(kubeConfig.Server)
+	data.ClusterCACertificate = types.StringValue(string(caCertificate))
+	data.Token = types.StringValue(kubeConfig.Token)
+	data.ExpiresAt = types.StringValue(kubeConfig.ExpirationTimestamp)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// Configure configures the ephemeral resource.
+func (e *ClusterKubeConfigEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	e.client = client
+}
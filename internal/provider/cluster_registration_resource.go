@@ -2,23 +2,48 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+const (
+	// defaultClusterWaitTimeout is the default create and update timeout.
+	defaultClusterWaitTimeout = 20 * time.Minute
+	// clusterWaitMaxBackoff is the longest interval between status polls.
+	clusterWaitMaxBackoff = 30 * time.Second
+)
+
+// clusterWaitInitialBackoff is the first interval between status polls.
+var clusterWaitInitialBackoff = 2 * time.Second
+
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &ClusterRegistrationResource{}
-	_ resource.ResourceWithConfigure   = &ClusterRegistrationResource{}
-	_ resource.ResourceWithImportState = &ClusterRegistrationResource{}
+	_ resource.Resource                     = &ClusterRegistrationResource{}
+	_ resource.ResourceWithConfigure        = &ClusterRegistrationResource{}
+	_ resource.ResourceWithImportState      = &ClusterRegistrationResource{}
+	_ resource.ResourceWithModifyPlan       = &ClusterRegistrationResource{}
+	_ resource.ResourceWithConfigValidators = &ClusterRegistrationResource{}
+	_ resource.ResourceWithIdentity         = &ClusterRegistrationResource{}
+	_ resource.ResourceWithUpgradeState     = &ClusterRegistrationResource{}
 )
 
 // NewClusterRegistrationResource returns a new resource.Resource.
@@ -39,6 +64,33 @@ type ClusterRegistrationResourceModel struct {
 	Description types.String `tfsdk:"description"`
 	Id          types.String `tfsdk:"id"`
 	LastUpdated types.String `tfsdk:"last_updated"`
+
+	CredentialsWo      types.String `tfsdk:"credentials_wo"`
+	CredentialsVersion types.Int64  `tfsdk:"credentials_version"`
+	CredentialsHash    types.String `tfsdk:"credentials_hash"`
+
+	WaitForReady types.Bool     `tfsdk:"wait_for_ready"`
+	WaitForSync  types.Bool     `tfsdk:"wait_for_sync"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+
+	DeletionProtection types.Bool `tfsdk:"deletion_protection"`
+
+	Labels      types.Map `tfsdk:"labels"`
+	LabelsAll   types.Map `tfsdk:"labels_all"`
+	Annotations types.Map `tfsdk:"annotations"`
+
+	AdoptExisting types.Bool `tfsdk:"adopt_existing"`
+
+	CreatedAt       types.String `tfsdk:"created_at"`
+	ResourceVersion types.String `tfsdk:"resource_version"`
+	Generation      types.Int64  `tfsdk:"generation"`
+	ForceUpdate     types.Bool   `tfsdk:"force_update"`
+}
+
+// ClusterRegistrationIdentityModel is the resource identity model.
+type ClusterRegistrationIdentityModel struct {
+	ClusterName types.String `tfsdk:"cluster_name"`
+	Id          types.String `tfsdk:"id"`
 }
 
 // Metadata returns the resource type name.
@@ -47,9 +99,12 @@ func (r *ClusterRegistrationResource) Metadata(_ context.Context, req resource.M
 }
 
 // Schema returns the resource schema.
-func (r *ClusterRegistrationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *ClusterRegistrationResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manage cluster registration",
+		// Version 1 stores last_updated in RFC3339 format and always sets
+		// credentials_hash for credentials stored in state.
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"cluster_name": schema.StringAttribute{
 				Required:    true,
@@ -65,9 +120,33 @@ func (r *ClusterRegistrationResource) Schema(_ context.Context, _ resource.Schem
 			"credentials": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "Path to kubeconfig file",
+				Description: "Path to kubeconfig file. The value is stored in state, prefer `credentials_wo` on Terraform 1.11 and later",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						credentialsRequiresReplace,
+						"Changing credentials requires replacement, removing them does not.",
+						"Changing credentials requires replacement, removing them does not.",
+					),
+				},
+			},
+			"credentials_wo": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Description: "Kubeconfig content, never stored in plan or state. Requires Terraform 1.11 or later",
+			},
+			"credentials_version": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Version of `credentials_wo`, change it to rotate the credentials of the registered cluster",
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("credentials_wo")),
+				},
+			},
+			"credentials_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 hash of the registered kubeconfig",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"description": schema.StringAttribute{
@@ -83,7 +162,204 @@ func (r *ClusterRegistrationResource) Schema(_ context.Context, _ resource.Schem
 			},
 			"last_updated": schema.StringAttribute{
 				Computed:    true,
-				Description: "Last updated timestamp",
+				Description: "Time the cluster was last modified in Karpor, in RFC3339 format",
+			},
+			"created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Time the cluster was registered in Karpor, in RFC3339 format",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"resource_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "Resource version of the Karpor cluster object, changes on every modification",
+			},
+			"generation": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Generation of the Karpor cluster object, changes when its spec is modified",
+			},
+			"wait_for_ready": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Wait for Karpor to connect to the cluster after registration, by default it is false",
+			},
+			"wait_for_sync": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Wait for Karpor to connect to the cluster and complete the initial resource sync after registration, by default it is false",
+			},
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Labels of the cluster, merged over the provider `default_labels`",
+			},
+			"labels_all": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Effective labels of the cluster, including the provider `default_labels` and excluding labels set by Karpor itself",
+			},
+			"annotations": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Annotations of the cluster",
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Adopt a cluster already registered under the same name instead of failing, as long as it points at the same API server as the credentials, by default it is false",
+			},
+			"force_update": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Overwrite changes made to the cluster in Karpor since it was last read instead of failing, by default it is false",
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Prevent the cluster from being destroyed or replaced, by default it is the provider `deletion_protection` setting",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
+	}
+}
+
+// UpgradeState returns the state upgraders from prior schema versions.
+func (r *ClusterRegistrationResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"cluster_name": schema.StringAttribute{
+						Required: true,
+					},
+					"display_name": schema.StringAttribute{
+						Optional: true,
+					},
+					"credentials": schema.StringAttribute{
+						Optional:  true,
+						Sensitive: true,
+					},
+					"description": schema.StringAttribute{
+						Optional: true,
+					},
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"last_updated": schema.StringAttribute{
+						Computed: true,
+					},
+				},
+			},
+			StateUpgrader: r.upgradeStateV0,
+		},
+	}
+}
+
+// clusterRegistrationResourceModelV0 is the resource model of schema version 0.
+type clusterRegistrationResourceModelV0 struct {
+	ClusterName types.String `tfsdk:"cluster_name"`
+	DisplayName types.String `tfsdk:"display_name"`
+	Credentials types.String `tfsdk:"credentials"`
+	Description types.String `tfsdk:"description"`
+	Id          types.String `tfsdk:"id"`
+	LastUpdated types.String `tfsdk:"last_updated"`
+}
+
+// upgradeStateV0 upgrades the state from schema version 0. The attributes
+// added since are set to their defaults, the next refresh reads the cluster
+// metadata and labels from Karpor.
+func (r *ClusterRegistrationResource) upgradeStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior clusterRegistrationResourceModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deletionProtection := false
+	if r.client != nil {
+		deletionProtection = r.client.DeletionProtection
+	}
+	state := ClusterRegistrationResourceModel{
+		ClusterName: prior.ClusterName,
+		DisplayName: prior.DisplayName,
+		Credentials: prior.Credentials,
+		Description: prior.Description,
+		Id:          prior.Id,
+		LastUpdated: upgradeLastUpdated(prior.LastUpdated),
+
+		CredentialsWo:      types.StringNull(),
+		CredentialsVersion: types.Int64Null(),
+		CredentialsHash:    hashCredentials(prior.Credentials.ValueString()),
+
+		WaitForReady: types.BoolValue(false),
+		WaitForSync:  types.BoolValue(false),
+		Timeouts: timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"update": types.StringType,
+		})},
+
+		DeletionProtection: types.BoolValue(deletionProtection),
+
+		Labels:      types.MapNull(types.StringType),
+		LabelsAll:   types.MapNull(types.StringType),
+		Annotations: types.MapNull(types.StringType),
+
+		AdoptExisting: types.BoolValue(false),
+
+		CreatedAt:       types.StringNull(),
+		ResourceVersion: types.StringNull(),
+		Generation:      types.Int64Null(),
+		ForceUpdate:     types.BoolValue(false),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// upgradeLastUpdated converts a last_updated timestamp written in the RFC850
+// format of schema version 0 to RFC3339. Timestamps in a time zone that
+// cannot be resolved are kept as they are, unparseable timestamps become
+// null, the next refresh reads the timestamp from Karpor.
+func upgradeLastUpdated(lastUpdated types.String) types.String {
+	if lastUpdated.IsNull() || lastUpdated.IsUnknown() {
+		return lastUpdated
+	}
+
+	// Version 0 wrote the timestamp in the local time zone
+	if t, err := time.ParseInLocation(time.RFC850, lastUpdated.ValueString(), time.Local); err == nil {
+		// Zone abbreviations other than UTC, GMT and those of the local
+		// time zone are read with a made-up offset of 0
+		if name, offset := t.Zone(); offset == 0 && name != "UTC" && name != "GMT" {
+			return lastUpdated
+		}
+		return types.StringValue(t.Format(time.RFC3339))
+	}
+	if t, err := time.Parse(time.RFC3339, lastUpdated.ValueString()); err == nil {
+		return types.StringValue(t.Format(time.RFC3339))
+	}
+	return types.StringNull()
+}
+
+// IdentitySchema returns the resource identity schema.
+func (r *ClusterRegistrationResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"cluster_name": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description:       "Unique name for the cluster",
+			},
+			"id": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description:       "Unique identifier assigned by Karpor",
 			},
 		},
 	}
@@ -97,24 +373,84 @@ func (c *ClusterRegistrationResource) Create(ctx context.Context, req resource.C
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultClusterWaitTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// Write-only credentials are only available in the configuration
+	var credentialsWo types.String
+	diags = req.Config.GetAttribute(ctx, path.Root("credentials_wo"), &credentialsWo)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	kubeConfig := plan.Credentials.ValueString()
+	if !credentialsWo.IsNull() {
+		kubeConfig = credentialsWo.ValueString()
+	}
+
 	// Validate the kubeconfig file
-	success, err := c.client.ValidateClusterConfig(ctx, &plan)
-	if !success || err != nil {
+	success, err := c.client.ValidateClusterConfig(ctx, kubeConfig)
+	if err != nil {
 		resp.Diagnostics.AddError("Invalid kubeconfig file", err.Error())
 		return
 	}
+	if !success {
+		resp.Diagnostics.AddError("Invalid kubeconfig file", "Karpor rejected the kubeconfig of cluster "+plan.ClusterName.ValueString()+".")
+		return
+	}
 	tflog.Info(ctx, "Valid kubeconfig file")
 
-	// Register the cluster
-	uid, err := c.client.RegisterCluster(ctx, &plan)
-	if err != nil {
+	// Register the cluster with the provider default labels
+	if plan.LabelsAll.IsUnknown() {
+		plan.LabelsAll = effectiveLabels(c.client, &plan)
+	}
+	uid, err := c.client.RegisterCluster(ctx, &plan, kubeConfig)
+	if IsConflict(err) {
+		if !plan.AdoptExisting.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cluster_name"),
+				"Cluster Already Exists",
+				fmt.Sprintf("A cluster named %s is already registered in Karpor. Import it, or set adopt_existing = true "+
+					"to adopt it when it points at the same API server.", plan.ClusterName.ValueString()),
+			)
+			return
+		}
+		var adoptDiags diag.Diagnostics
+		uid, adoptDiags = c.adoptCluster(ctx, &plan, kubeConfig)
+		resp.Diagnostics.Append(adoptDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else if err != nil {
 		resp.Diagnostics.AddError("Failed to register cluster", err.Error())
 		return
 	}
 
 	// Set the resource ID (uid)
 	plan.Id = types.StringValue(uid)
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	if plan.DeletionProtection.IsUnknown() {
+		plan.DeletionProtection = types.BoolValue(c.client.DeletionProtection)
+	}
+	plan.CredentialsHash = hashCredentials(kubeConfig)
+
+	// The next read fills in the metadata if it cannot be read now
+	remoteState, err := c.client.GetCluster(ctx, plan.ClusterName.ValueString())
+	if err == nil {
+		plan.setServerMetadata(remoteState)
+	} else {
+		// Zero values are null
+		plan.setServerMetadata(&ClusterRegistrationResourceModel{})
+		resp.Diagnostics.AddWarning(
+			"Error Reading Karpor Cluster",
+			"Cluster "+plan.ClusterName.ValueString()+" was registered, but its metadata could not be read and will be refreshed on the next plan: "+err.Error(),
+		)
+	}
 
 	// Save the resource state
 	diags = resp.State.Set(ctx, plan)
@@ -122,6 +458,15 @@ func (c *ClusterRegistrationResource) Create(ctx context.Context, req resource.C
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, clusterIdentity(&plan))...)
+
+	// Terraform taints the registered cluster if it never becomes ready
+	if plan.WaitForReady.ValueBool() || plan.WaitForSync.ValueBool() {
+		if err := waitForCluster(ctx, c.client, plan.ClusterName.ValueString(), plan.WaitForSync.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Cluster did not become ready", err.Error())
+			return
+		}
+	}
 }
 
 // Read reads the resource.
@@ -149,6 +494,27 @@ func (c *ClusterRegistrationResource) Read(ctx context.Context, req resource.Rea
 	state.DisplayName = remoteState.DisplayName
 	state.Description = remoteState.Description
 	state.Id = remoteState.Id
+	state.Labels = managedStringMap(remoteState.Labels, state.Labels, c.client.DefaultLabels)
+	state.LabelsAll = managedStringMap(remoteState.Labels, state.LabelsAll, nil)
+	state.Annotations = managedStringMap(remoteState.Annotations, state.Annotations, nil)
+	state.setServerMetadata(remoteState)
+
+	// Imported clusters have no wait settings yet
+	if state.WaitForReady.IsNull() {
+		state.WaitForReady = types.BoolValue(false)
+	}
+	if state.WaitForSync.IsNull() {
+		state.WaitForSync = types.BoolValue(false)
+	}
+	if state.DeletionProtection.IsNull() {
+		state.DeletionProtection = types.BoolValue(c.client.DeletionProtection)
+	}
+	if state.AdoptExisting.IsNull() {
+		state.AdoptExisting = types.BoolValue(false)
+	}
+	if state.ForceUpdate.IsNull() {
+		state.ForceUpdate = types.BoolValue(false)
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -156,6 +522,7 @@ func (c *ClusterRegistrationResource) Read(ctx context.Context, req resource.Rea
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, clusterIdentity(&state))...)
 }
 
 // Update updates the resource.
@@ -168,12 +535,77 @@ func (c *ClusterRegistrationResource) Update(ctx context.Context, req resource.U
 		return
 	}
 
-	// Update the cluster
-	success, err := c.client.UpdateCluster(ctx, &plan)
-	if !success || err != nil {
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultClusterWaitTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	var state ClusterRegistrationResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var credentialsWo types.String
+	diags = req.Config.GetAttribute(ctx, path.Root("credentials_wo"), &credentialsWo)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Rotate the credentials when their version changes, or when credentials
+	// unknown at plan time turn out to have changed
+	kubeConfig := ""
+	plan.CredentialsHash = state.CredentialsHash
+	switch {
+	case !plan.CredentialsVersion.Equal(state.CredentialsVersion) && !credentialsWo.IsNull():
+		kubeConfig = credentialsWo.ValueString()
+	case !plan.Credentials.IsNull() && !plan.Credentials.Equal(state.Credentials):
+		kubeConfig = plan.Credentials.ValueString()
+	}
+	if kubeConfig != "" {
+		success, err := c.client.ValidateClusterConfig(ctx, kubeConfig)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid kubeconfig file", err.Error())
+			return
+		}
+		if !success {
+			resp.Diagnostics.AddError("Invalid kubeconfig file", "Karpor rejected the kubeconfig of cluster "+plan.ClusterName.ValueString()+".")
+			return
+		}
+		plan.CredentialsHash = hashCredentials(kubeConfig)
+	}
+
+	// Update the cluster, unless it was modified since it was last read
+	plan.ResourceVersion = state.ResourceVersion
+	if plan.ForceUpdate.ValueBool() {
+		plan.ResourceVersion = types.StringNull()
+	}
+	if plan.LabelsAll.IsUnknown() {
+		plan.LabelsAll = effectiveLabels(c.client, &plan)
+	}
+	success, err := c.client.UpdateCluster(ctx, &plan, kubeConfig)
+	if IsConflict(err) {
+		resp.Diagnostics.AddError(
+			"Cluster Modified Outside Terraform",
+			fmt.Sprintf("Cluster %s was modified in Karpor after Terraform last read it at resource version %s. "+
+				"Run terraform apply again to refresh the cluster and review the changes, or set force_update = true to overwrite them.",
+				plan.ClusterName.ValueString(), state.ResourceVersion.ValueString()),
+		)
+		return
+	}
+	if err != nil {
 		resp.Diagnostics.AddError("Failed to update cluster", err.Error())
 		return
 	}
+	if !success {
+		resp.Diagnostics.AddError("Failed to update cluster", "Karpor did not update cluster "+plan.ClusterName.ValueString()+".")
+		return
+	}
 
 	// Fetch updated items from GetCluster as UpdateCluster items are not populated.
 	remoteState, err := c.client.GetCluster(ctx, plan.ClusterName.ValueString())
@@ -188,13 +620,22 @@ func (c *ClusterRegistrationResource) Update(ctx context.Context, req resource.U
 	// Update resource state with updated items and timestamp
 	plan.DisplayName = remoteState.DisplayName
 	plan.Description = remoteState.Description
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	plan.setServerMetadata(remoteState)
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, clusterIdentity(&plan))...)
+
+	// Karpor reconnects to the cluster after the credentials are rotated
+	if kubeConfig != "" && (plan.WaitForReady.ValueBool() || plan.WaitForSync.ValueBool()) {
+		if err := waitForCluster(ctx, c.client, plan.ClusterName.ValueString(), plan.WaitForSync.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Cluster did not become ready", err.Error())
+			return
+		}
+	}
 }
 
 // Delete deletes the resource.
@@ -207,21 +648,168 @@ func (c *ClusterRegistrationResource) Delete(ctx context.Context, req resource.D
 		return
 	}
 
+	if state.DeletionProtection.ValueBool() {
+		addDeletionProtectionError(&resp.Diagnostics, state.ClusterName.ValueString(), "destroyed")
+		return
+	}
+
 	// Delete existing order
 	success, err := c.client.DeleteCluster(ctx, &state)
-	if !success || err != nil {
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting Karpor Cluster",
 			"Could not delete cluster, unexpected error: "+err.Error(),
 		)
 		return
 	}
+	if !success {
+		resp.Diagnostics.AddError(
+			"Error Deleting Karpor Cluster",
+			"Karpor did not delete cluster "+state.ClusterName.ValueString()+".",
+		)
+		return
+	}
+}
+
+// ModifyPlan applies the provider-level deletion protection default, enforces
+// deletion protection and marks the credentials hash as unknown when the
+// credentials are rotated.
+func (c *ClusterRegistrationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var state ClusterRegistrationResourceModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Destroy
+	if req.Plan.Raw.IsNull() {
+		if state.DeletionProtection.ValueBool() {
+			addDeletionProtectionError(&resp.Diagnostics, state.ClusterName.ValueString(), "destroyed")
+		}
+		return
+	}
+
+	var plan ClusterRegistrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var deletionProtection types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("deletion_protection"), &deletionProtection)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if deletionProtection.IsNull() && c.client != nil {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("deletion_protection"), types.BoolValue(c.client.DeletionProtection))...)
+	}
+
+	// The effective labels are known when the labels and the provider are
+	if !plan.Labels.IsUnknown() && c.client != nil {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("labels_all"), effectiveLabels(c.client, &plan))...)
+	}
+
+	// Nothing else to do on create
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	// Replacing the cluster deletes it, which deletion protection forbids.
+	// Unknown values may turn out unchanged, Delete checks them at apply.
+	replace := (!plan.ClusterName.IsUnknown() && !plan.ClusterName.Equal(state.ClusterName)) ||
+		(!plan.Credentials.IsNull() && !plan.Credentials.IsUnknown() && !plan.Credentials.Equal(state.Credentials))
+	if replace && state.DeletionProtection.ValueBool() {
+		addDeletionProtectionError(&resp.Diagnostics, state.ClusterName.ValueString(), "replaced")
+		return
+	}
+
+	if !plan.CredentialsVersion.Equal(state.CredentialsVersion) || plan.Credentials.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("credentials_hash"), types.StringUnknown())...)
+	}
 }
 
-// ImportState imports the resource.
+// ConfigValidators returns the resource level validators.
+func (c *ClusterRegistrationResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("credentials"),
+			path.MatchRoot("credentials_wo"),
+		),
+		resourcevalidator.PreferWriteOnlyAttribute(
+			path.MatchRoot("credentials"),
+			path.MatchRoot("credentials_wo"),
+		),
+	}
+}
+
+// ImportState imports the resource by cluster name, by "uid:<uid>", by
+// "<cluster_name>/<uid>" or by resource identity.
 func (c *ClusterRegistrationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var identity ClusterRegistrationIdentityModel
+	if req.ID != "" {
+		switch {
+		case strings.HasPrefix(req.ID, "uid:"):
+			identity.Id = types.StringValue(strings.TrimPrefix(req.ID, "uid:"))
+		case strings.Contains(req.ID, "/"):
+			clusterName, uid, _ := strings.Cut(req.ID, "/")
+			identity.ClusterName = types.StringValue(clusterName)
+			identity.Id = types.StringValue(uid)
+		default:
+			identity.ClusterName = types.StringValue(req.ID)
+		}
+	} else {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if identity.ClusterName.ValueString() == "" && identity.Id.ValueString() == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import Identifier",
+			"Expected a cluster name, \"uid:<uid>\" or \"<cluster_name>/<uid>\", got: "+req.ID,
+		)
+		return
+	}
+
+	// Resolve the cluster name from the UID
+	if identity.ClusterName.ValueString() == "" {
+		cluster, err := c.client.FindClusterByUID(ctx, identity.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Importing Karpor Cluster",
+				"Could not find Karpor cluster with uid "+identity.Id.String()+": "+err.Error(),
+			)
+			return
+		}
+		identity.ClusterName = cluster.ClusterName
+	} else if identity.Id.ValueString() != "" {
+		// Make sure the name and UID refer to the same cluster
+		cluster, err := c.client.GetCluster(ctx, identity.ClusterName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Importing Karpor Cluster",
+				"Could not read Karpor cluster "+identity.ClusterName.String()+": "+err.Error(),
+			)
+			return
+		}
+		if !cluster.Id.Equal(identity.Id) {
+			resp.Diagnostics.AddError(
+				"Error Importing Karpor Cluster",
+				fmt.Sprintf("Karpor cluster %s has uid %s, expected %s", identity.ClusterName, cluster.Id, identity.Id),
+			)
+			return
+		}
+	}
+
 	// Terraform will automatically call the resource's Read method to import the rest of the Terraform state
-	resource.ImportStatePassthroughID(ctx, path.Root("cluster_name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_name"), identity.ClusterName)...)
+	if !identity.Id.IsNull() {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.Id)...)
+	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, identity)...)
 }
 
 // Configure configures the resource.
@@ -238,3 +826,196 @@ func (c *ClusterRegistrationResource) Configure(ctx context.Context, req resourc
 	}
 	c.client = client
 }
+
+// adoptCluster takes over a cluster already registered under the planned name
+// after checking it points at the API server of kubeConfig, and returns its UID.
+func (c *ClusterRegistrationResource) adoptCluster(ctx context.Context, plan *ClusterRegistrationResourceModel, kubeConfig string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	clusterName := plan.ClusterName.ValueString()
+
+	config, err := parseKubeConfig(kubeConfig)
+	if err != nil {
+		diags.AddError("Invalid kubeconfig file", err.Error())
+		return "", diags
+	}
+	server, err := config.server()
+	if err != nil {
+		diags.AddError("Invalid kubeconfig file", err.Error())
+		return "", diags
+	}
+	endpoint, err := c.client.GetClusterEndpoint(ctx, clusterName)
+	if err != nil {
+		diags.AddError(
+			"Error Reading Karpor Cluster",
+			"Could not read existing Karpor cluster "+clusterName+": "+err.Error(),
+		)
+		return "", diags
+	}
+	if !sameServer(server, endpoint) {
+		diags.AddAttributeError(
+			path.Root("cluster_name"),
+			"Cluster Already Exists",
+			fmt.Sprintf("A cluster named %s is already registered in Karpor for API server %s, but the credentials point at %s, "+
+				"so it cannot be adopted. Choose another cluster_name or remove the existing cluster.", clusterName, endpoint, server),
+		)
+		return "", diags
+	}
+
+	// Bring the existing cluster in line with the configuration
+	if _, err := c.client.UpdateCluster(ctx, plan, kubeConfig); err != nil {
+		diags.AddError("Failed to adopt cluster", err.Error())
+		return "", diags
+	}
+	remote, err := c.client.GetCluster(ctx, clusterName)
+	if err != nil {
+		diags.AddError(
+			"Error Reading Karpor Cluster",
+			"Could not read Karpor Cluster "+clusterName+": "+err.Error(),
+		)
+		return "", diags
+	}
+	tflog.Info(ctx, "Adopted existing cluster", map[string]interface{}{
+		"cluster_name": clusterName,
+		"id":           remote.Id.ValueString(),
+	})
+	return remote.Id.ValueString(), diags
+}
+
+// effectiveLabels returns the labels of the cluster merged over the provider
+// default labels.
+func effectiveLabels(client *KarporClient, cluster *ClusterRegistrationResourceModel) types.Map {
+	labels := map[string]string{}
+	for k, v := range client.DefaultLabels {
+		labels[k] = v
+	}
+	for k, v := range stringMapValue(cluster.Labels) {
+		labels[k] = v
+	}
+	if len(labels) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	elements := make(map[string]attr.Value, len(labels))
+	for k, v := range labels {
+		elements[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// managedStringMap returns the remote labels or annotations without the
+// provider defaults and Karpor system keys the resource does not set itself,
+// so that neither shows up as drift.
+func managedStringMap(remote types.Map, prior types.Map, defaults map[string]string) types.Map {
+	priorElements := prior.Elements()
+	elements := map[string]attr.Value{}
+	for k, v := range remote.Elements() {
+		if _, managed := priorElements[k]; !managed {
+			if defaultValue, ok := defaults[k]; ok && v.Equal(types.StringValue(defaultValue)) {
+				continue
+			}
+			if isSystemMetadataKey(k) {
+				continue
+			}
+		}
+		elements[k] = v
+	}
+
+	// Keep an empty map from the configuration instead of turning it into null
+	if len(elements) == 0 {
+		if !prior.IsNull() && !prior.IsUnknown() && len(priorElements) == 0 {
+			return prior
+		}
+		return types.MapNull(types.StringType)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// systemMetadataDomains are the label and annotation prefixes owned by Karpor
+// and Kubernetes rather than by the user.
+var systemMetadataDomains = []string{"karpor.io", "kusionstack.io", "kubernetes.io", "k8s.io"}
+
+// isSystemMetadataKey reports whether a label or annotation key belongs to a
+// system domain or one of its subdomains.
+func isSystemMetadataKey(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	for _, domain := range systemMetadataDomains {
+		if prefix == domain || strings.HasSuffix(prefix, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// addDeletionProtectionError reports that a protected cluster cannot be
+// destroyed or replaced.
+func addDeletionProtectionError(diags *diag.Diagnostics, clusterName string, action string) {
+	diags.AddAttributeError(
+		path.Root("deletion_protection"),
+		"Cluster Deletion Protection Enabled",
+		fmt.Sprintf("Cluster %s cannot be %s while deletion_protection is enabled, which would remove its history from Karpor. "+
+			"Set deletion_protection = false and apply before destroying or replacing it.", clusterName, action),
+	)
+}
+
+// waitForCluster polls the cluster status with exponential backoff until the
+// cluster is healthy, and synced if waitForSync is set, or ctx is done.
+func waitForCluster(ctx context.Context, client *KarporClient, clusterName string, waitForSync bool) error {
+	backoff := clusterWaitInitialBackoff
+	for {
+		status, err := client.GetClusterStatus(ctx, clusterName)
+		if err == nil {
+			tflog.Debug(ctx, "Observed cluster status", map[string]interface{}{
+				"cluster_name": clusterName,
+				"status":       status.String(),
+			})
+			if status.Healthy && (!waitForSync || status.Synced) {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("timed out waiting for cluster %s, last error: %w", clusterName, err)
+			}
+			return fmt.Errorf("timed out waiting for cluster %s, last observed status: %s", clusterName, status)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, clusterWaitMaxBackoff)
+	}
+}
+
+// credentialsRequiresReplace replaces the cluster when its credentials change,
+// but not when they are removed, e.g. when moving to credentials_wo, nor when
+// they are unknown, Update rotates them if they changed at apply.
+func credentialsRequiresReplace(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.PlanValue.IsNull() && !req.PlanValue.IsUnknown() && !req.PlanValue.Equal(req.StateValue)
+}
+
+// hashCredentials returns the SHA-256 hash of a kubeconfig.
+func hashCredentials(kubeConfig string) types.String {
+	if kubeConfig == "" {
+		return types.StringNull()
+	}
+	sum := sha256.Sum256([]byte(kubeConfig))
+	return types.StringValue(hex.EncodeToString(sum[:]))
+}
+
+// setServerMetadata copies the timestamps and versions Karpor reports for the
+// cluster object.
+func (m *ClusterRegistrationResourceModel) setServerMetadata(remote *ClusterRegistrationResourceModel) {
+	m.CreatedAt = remote.CreatedAt
+	m.LastUpdated = remote.LastUpdated
+	m.ResourceVersion = remote.ResourceVersion
+	m.Generation = remote.Generation
+}
+
+// clusterIdentity returns the resource identity of a cluster.
+func clusterIdentity(cluster *ClusterRegistrationResourceModel) ClusterRegistrationIdentityModel {
+	return ClusterRegistrationIdentityModel{
+		ClusterName: cluster.ClusterName,
+		Id:          cluster.Id,
+	}
+}
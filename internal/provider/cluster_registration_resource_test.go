@@ -1,12 +1,33 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestAccClusterRegistration(t *testing.T) {
@@ -51,6 +72,16 @@ func TestAccClusterRegistration(t *testing.T) {
 						tfjsonpath.New("last_updated"),
 						knownvalue.NotNull(),
 					),
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("created_at"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("resource_version"),
+						knownvalue.NotNull(),
+					),
 				},
 			},
 			// Update
@@ -61,9 +92,29 @@ func TestAccClusterRegistration(t *testing.T) {
 					display_name = "test-display-name-updated"
 					credentials  =  file("~/config")
 					description  = "test-description-updated"
+					labels = {
+						env = "test"
+					}
+					annotations = {
+						"example.com/owner" = "team-a"
+					}
 				}
 				`,
 				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("labels"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"env": knownvalue.StringExact("test"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("annotations"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"example.com/owner": knownvalue.StringExact("team-a"),
+						}),
+					),
 					statecheck.ExpectKnownValue(
 						"karpor_cluster_registration.test",
 						tfjsonpath.New("display_name"),
@@ -81,7 +132,625 @@ func TestAccClusterRegistration(t *testing.T) {
 					),
 				},
 			},
+			// Import by cluster name
+			{
+				ResourceName:            "karpor_cluster_registration.test",
+				ImportState:             true,
+				ImportStateId:           "test-cluster",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"credentials", "credentials_hash"},
+			},
+			// Import by uid
+			{
+				ResourceName: "karpor_cluster_registration.test",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return "uid:" + s.RootModule().Resources["karpor_cluster_registration.test"].Primary.ID, nil
+				},
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"credentials", "credentials_hash"},
+			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
 }
+
+func TestAccClusterRegistrationWriteOnlyCredentials(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			// Create with write-only credentials
+			{
+				Config: providerConfig + `
+				resource "karpor_cluster_registration" "test" {
+					cluster_name        = "test-cluster-wo"
+					credentials_wo      = file("~/config")
+					credentials_version = 1
+					wait_for_ready      = true
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("credentials_wo"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("credentials_hash"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Rotate the credentials in place
+			{
+				Config: providerConfig + `
+				resource "karpor_cluster_registration" "test" {
+					cluster_name        = "test-cluster-wo"
+					credentials_wo      = file("~/config")
+					credentials_version = 2
+					wait_for_ready      = true
+				}
+				`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("karpor_cluster_registration.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("credentials_version"),
+						knownvalue.Int64Exact(2),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccClusterRegistrationIdentity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				resource "karpor_cluster_registration" "test" {
+					cluster_name = "test-cluster-identity"
+					credentials  = file("~/config")
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentityValueMatchesState(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("cluster_name"),
+					),
+					statecheck.ExpectIdentityValueMatchesState(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("id"),
+					),
+				},
+			},
+			// Import with an import block using the resource identity
+			{
+				ResourceName:    "karpor_cluster_registration.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+// setClusterWaitInitialBackoff sets the first interval between status polls
+// until the test ends.
+func setClusterWaitInitialBackoff(t *testing.T, backoff time.Duration) {
+	previous := clusterWaitInitialBackoff
+	clusterWaitInitialBackoff = backoff
+	t.Cleanup(func() { clusterWaitInitialBackoff = previous })
+}
+
+func TestWaitForCluster(t *testing.T) {
+	setClusterWaitInitialBackoff(t, time.Millisecond)
+
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Healthy on the second poll, synced on the third one
+		n := atomic.AddInt32(&polls, 1)
+		_, _ = fmt.Fprintf(w, `{"success": true, "data": {"status": {"phase": "Running", "healthy": %t, "synced": %t}}}`, n >= 2, n >= 3)
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := waitForCluster(context.Background(), client, "test-cluster", false); err != nil {
+		t.Fatal(err)
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 polls until ready, got %d", polls)
+	}
+
+	if err := waitForCluster(context.Background(), client, "test-cluster", true); err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 {
+		t.Errorf("expected 3 polls until synced, got %d", polls)
+	}
+}
+
+func TestWaitForClusterTimeout(t *testing.T) {
+	setClusterWaitInitialBackoff(t, time.Millisecond)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success": true, "data": {"status": {"phase": "Pending", "healthy": false, "message": "connection refused"}}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = waitForCluster(ctx, client, "test-cluster", false)
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected the last observed status in the error, got: %v", err)
+	}
+}
+
+func TestAccClusterRegistrationDeletionProtection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				resource "karpor_cluster_registration" "test" {
+					cluster_name        = "test-cluster-protected"
+					credentials         = file("~/config")
+					deletion_protection = true
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("deletion_protection"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+			// Renaming the cluster would replace it
+			{
+				Config: providerConfig + `
+				resource "karpor_cluster_registration" "test" {
+					cluster_name        = "test-cluster-protected-renamed"
+					credentials         = file("~/config")
+					deletion_protection = true
+				}
+				`,
+				ExpectError: regexp.MustCompile("Cluster Deletion Protection Enabled"),
+			},
+			// Turn deletion protection off so the cluster can be destroyed
+			{
+				Config: providerConfig + `
+				resource "karpor_cluster_registration" "test" {
+					cluster_name        = "test-cluster-protected"
+					credentials         = file("~/config")
+					deletion_protection = false
+				}
+				`,
+			},
+		},
+	})
+}
+
+func TestIsSystemMetadataKey(t *testing.T) {
+	testCases := map[string]bool{
+		"env":                         false,
+		"example.com/owner":           false,
+		"notkarpor.io/owner":          false,
+		"karpor.io/managed":           true,
+		"search.karpor.io/synced":     true,
+		"kubernetes.io/metadata.name": true,
+		"node.k8s.io/zone":            true,
+	}
+	for key, expected := range testCases {
+		if got := isSystemMetadataKey(key); got != expected {
+			t.Errorf("isSystemMetadataKey(%q): expected %t, got %t", key, expected, got)
+		}
+	}
+}
+
+func TestManagedStringMap(t *testing.T) {
+	defaults := map[string]string{"managed-by": "terraform", "team": "platform"}
+
+	testCases := map[string]struct {
+		remote   map[string]string
+		prior    types.Map
+		expected types.Map
+	}{
+		"defaults are hidden": {
+			remote:   map[string]string{"env": "prod", "managed-by": "terraform", "team": "platform"},
+			prior:    types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("prod")}),
+			expected: types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("prod")}),
+		},
+		"overridden defaults are kept": {
+			remote: map[string]string{"managed-by": "terraform", "team": "search"},
+			prior:  types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("search")}),
+			expected: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"team": types.StringValue("search"),
+			}),
+		},
+		"defaults set on the resource are kept": {
+			remote:   map[string]string{"managed-by": "terraform"},
+			prior:    types.MapValueMust(types.StringType, map[string]attr.Value{"managed-by": types.StringValue("terraform")}),
+			expected: types.MapValueMust(types.StringType, map[string]attr.Value{"managed-by": types.StringValue("terraform")}),
+		},
+		"drift is reported": {
+			remote:   map[string]string{"env": "dev", "extra": "value"},
+			prior:    types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("prod")}),
+			expected: types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("dev"), "extra": types.StringValue("value")}),
+		},
+		"empty map is kept": {
+			remote:   map[string]string{"managed-by": "terraform"},
+			prior:    types.MapValueMust(types.StringType, map[string]attr.Value{}),
+			expected: types.MapValueMust(types.StringType, map[string]attr.Value{}),
+		},
+		"system keys are hidden": {
+			remote:   map[string]string{"env": "prod", "karpor.io/synced": "true", "cluster.karpor.io/region": "cn"},
+			prior:    types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("prod")}),
+			expected: types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("prod")}),
+		},
+		"system keys set on the resource are kept": {
+			remote:   map[string]string{"karpor.io/tier": "gold"},
+			prior:    types.MapValueMust(types.StringType, map[string]attr.Value{"karpor.io/tier": types.StringValue("gold")}),
+			expected: types.MapValueMust(types.StringType, map[string]attr.Value{"karpor.io/tier": types.StringValue("gold")}),
+		},
+		"null map is kept": {
+			remote:   map[string]string{},
+			prior:    types.MapNull(types.StringType),
+			expected: types.MapNull(types.StringType),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			elements := map[string]attr.Value{}
+			for k, v := range testCase.remote {
+				elements[k] = types.StringValue(v)
+			}
+			remote := types.MapValueMust(types.StringType, elements)
+
+			got := managedStringMap(remote, testCase.prior, defaults)
+			if !got.Equal(testCase.expected) {
+				t.Errorf("expected %s, got %s", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestAccClusterRegistrationDefaultLabels(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "karpor" {
+					api_endpoint    = "https://127.0.0.1:7443"
+					api_key         = "your-api-key-here"
+					skip_tls_verify = true
+
+					default_labels {
+						labels = {
+							managed-by = "terraform"
+							team       = "platform"
+						}
+					}
+				}
+
+				resource "karpor_cluster_registration" "test" {
+					cluster_name = "test-cluster-labels"
+					credentials  = file("~/config")
+					labels = {
+						env  = "test"
+						team = "search"
+					}
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("labels"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"env":  knownvalue.StringExact("test"),
+							"team": knownvalue.StringExact("search"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registration.test",
+						tfjsonpath.New("labels_all"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"env":        knownvalue.StringExact("test"),
+							"managed-by": knownvalue.StringExact("terraform"),
+							"team":       knownvalue.StringExact("search"),
+						}),
+					),
+				},
+				// Labels added by Karpor itself must not cause a diff
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestClusterRegistrationUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	server := providerserver.NewProtocol6(New("test")())()
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateType := schemaResp.ResourceSchemas["karpor_cluster_registration"].ValueType()
+
+	// State written by the first release of the resource
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "karpor_cluster_registration",
+		Version:  0,
+		RawState: &tfprotov6.RawState{
+			JSON: []byte(`{
+				"cluster_name": "test-cluster",
+				"display_name": "test-display-name",
+				"credentials": "apiVersion: v1\nkind: Config\n",
+				"description": "test-description",
+				"id": "3f1b2c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+				"last_updated": "Wednesday, 01-May-24 08:00:00 UTC"
+			}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+
+	upgraded, err := resp.UpgradedState.Unmarshal(stateType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attributes := map[string]tftypes.Value{}
+	if err := upgraded.As(&attributes); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"cluster_name":     "test-cluster",
+		"display_name":     "test-display-name",
+		"description":      "test-description",
+		"id":               "3f1b2c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+		"last_updated":     "2024-05-01T08:00:00Z",
+		"credentials_hash": hashCredentials("apiVersion: v1\nkind: Config\n").ValueString(),
+	}
+	for name, want := range expected {
+		var got string
+		if err := attributes[name].As(&got); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if got != want {
+			t.Errorf("expected %s to be %q, got %q", name, want, got)
+		}
+	}
+
+	var waitForReady bool
+	if err := attributes["wait_for_ready"].As(&waitForReady); err != nil {
+		t.Fatal(err)
+	}
+	if waitForReady {
+		t.Error("expected wait_for_ready to default to false")
+	}
+}
+
+func TestUpgradeLastUpdated(t *testing.T) {
+	cases := map[string]types.String{
+		"Wednesday, 01-May-24 08:00:00 UTC": types.StringValue("2024-05-01T08:00:00Z"),
+		"2024-05-01T08:00:00Z":              types.StringValue("2024-05-01T08:00:00Z"),
+		"yesterday":                         types.StringNull(),
+	}
+	for input, want := range cases {
+		if got := upgradeLastUpdated(types.StringValue(input)); !got.Equal(want) {
+			t.Errorf("upgradeLastUpdated(%q) = %s, want %s", input, got, want)
+		}
+	}
+	if got := upgradeLastUpdated(types.StringNull()); !got.IsNull() {
+		t.Errorf("expected null to stay null, got %s", got)
+	}
+
+	// Zone abbreviations resolve in the local time zone version 0 wrote in
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+	time.Local = time.FixedZone("PDT", -7*60*60)
+	cases = map[string]types.String{
+		"Wednesday, 01-May-24 08:00:00 PDT": types.StringValue("2024-05-01T08:00:00-07:00"),
+		"Wednesday, 01-May-24 08:00:00 CST": types.StringValue("Wednesday, 01-May-24 08:00:00 CST"),
+	}
+	for input, want := range cases {
+		if got := upgradeLastUpdated(types.StringValue(input)); !got.Equal(want) {
+			t.Errorf("upgradeLastUpdated(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+// createClusterRegistration calls Create of the cluster registration resource
+// against a Karpor server with a configuration setting the given attributes.
+func createClusterRegistration(t *testing.T, handler http.HandlerFunc, attributes map[string]tftypes.Value) *fwresource.CreateResponse {
+	t.Helper()
+	ctx := context.Background()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &ClusterRegistrationResource{client: client}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+	raw := tftypes.NewValue(objectType, values)
+
+	resp := &fwresource.CreateResponse{
+		State:    tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+		Identity: &tfsdk.ResourceIdentity{},
+	}
+	identityResp := &fwresource.IdentitySchemaResponse{}
+	r.IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, identityResp)
+	resp.Identity.Schema = identityResp.IdentitySchema
+	resp.Identity.Raw = tftypes.NewValue(identityResp.IdentitySchema.Type().TerraformType(ctx), nil)
+
+	r.Create(ctx, fwresource.CreateRequest{
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: raw},
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: raw},
+	}, resp)
+	return resp
+}
+
+func TestClusterRegistrationCreateRejectedKubeConfig(t *testing.T) {
+	resp := createClusterRegistration(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success": false, "message": "invalid kubeconfig"}`))
+	}, map[string]tftypes.Value{
+		"cluster_name": tftypes.NewValue(tftypes.String, "test-cluster"),
+		"credentials":  tftypes.NewValue(tftypes.String, "apiVersion: v1\nkind: Config\n"),
+	})
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Invalid kubeconfig file" {
+		t.Errorf("expected an invalid kubeconfig error, got %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("expected no state for a cluster that was not registered")
+	}
+}
+
+func TestClusterRegistrationCreateUnreadableMetadata(t *testing.T) {
+	resp := createClusterRegistration(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/config/validate"):
+			_, _ = w.Write([]byte(`{"success": true}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{"success": true, "data": {"metadata": {"uid": "test-uid"}}}`))
+		}
+	}, map[string]tftypes.Value{
+		"cluster_name": tftypes.NewValue(tftypes.String, "test-cluster"),
+		"credentials":  tftypes.NewValue(tftypes.String, "apiVersion: v1\nkind: Config\n"),
+	})
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected no errors, which would taint the registered cluster, got %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning for the unread metadata, got %v", resp.Diagnostics)
+	}
+	var id, resourceVersion types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("id"), &id)...)
+	resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("resource_version"), &resourceVersion)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if id.ValueString() != "test-uid" || !resourceVersion.IsNull() {
+		t.Errorf("expected the registered cluster in state without metadata, got id %s and resource version %s", id, resourceVersion)
+	}
+}
+
+func TestClusterRegistrationModifyPlanUnknownCredentials(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewKarporClient("http://localhost", "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &ClusterRegistrationResource{client: client}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	value := func(credentials tftypes.Value) tftypes.Value {
+		values := map[string]tftypes.Value{}
+		for name, attributeType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+		values["cluster_name"] = tftypes.NewValue(tftypes.String, "test-cluster")
+		values["id"] = tftypes.NewValue(tftypes.String, "test-uid")
+		values["deletion_protection"] = tftypes.NewValue(tftypes.Bool, true)
+		values["credentials"] = credentials
+		values["credentials_hash"] = tftypes.NewValue(tftypes.String, hashCredentials("old-kubeconfig").ValueString())
+		return tftypes.NewValue(objectType, values)
+	}
+	state := value(tftypes.NewValue(tftypes.String, "old-kubeconfig"))
+
+	modifyPlan := func(credentials tftypes.Value) *fwresource.ModifyPlanResponse {
+		plan := value(credentials)
+		resp := &fwresource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan}}
+		r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: plan},
+			Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+			State:  tfsdk.State{Schema: schemaResp.Schema, Raw: state},
+		}, resp)
+		return resp
+	}
+
+	// Credentials unknown at plan time may not change
+	resp := modifyPlan(tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected no deletion protection error for unknown credentials, got %v", resp.Diagnostics)
+	}
+	var credentialsHash types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("credentials_hash"), &credentialsHash)...)
+	if !credentialsHash.IsUnknown() {
+		t.Errorf("expected the credentials hash to be unknown, got %s", credentialsHash)
+	}
+
+	resp = modifyPlan(tftypes.NewValue(tftypes.String, "new-kubeconfig"))
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Cluster Deletion Protection Enabled" {
+		t.Errorf("expected a deletion protection error for changed credentials, got %v", resp.Diagnostics)
+	}
+}
+
+func TestCredentialsRequiresReplace(t *testing.T) {
+	cases := []struct {
+		plan, state types.String
+		want        bool
+	}{
+		{types.StringValue("new"), types.StringValue("old"), true},
+		{types.StringValue("old"), types.StringValue("old"), false},
+		{types.StringUnknown(), types.StringValue("old"), false},
+		{types.StringNull(), types.StringValue("old"), false},
+	}
+	for _, c := range cases {
+		resp := &stringplanmodifier.RequiresReplaceIfFuncResponse{}
+		credentialsRequiresReplace(context.Background(), planmodifier.StringRequest{PlanValue: c.plan, StateValue: c.state}, resp)
+		if resp.RequiresReplace != c.want {
+			t.Errorf("credentialsRequiresReplace(%s, %s) = %t, want %t", c.plan, c.state, resp.RequiresReplace, c.want)
+		}
+	}
+}
@@ -0,0 +1,655 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultClusterRegistrationsParallelism is the number of clusters processed
+// concurrently when parallelism is not set.
+const defaultClusterRegistrationsParallelism = 5
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &ClusterRegistrationsResource{}
+	_ resource.ResourceWithConfigure  = &ClusterRegistrationsResource{}
+	_ resource.ResourceWithModifyPlan = &ClusterRegistrationsResource{}
+)
+
+// NewClusterRegistrationsResource returns a new resource.Resource.
+func NewClusterRegistrationsResource() resource.Resource {
+	return &ClusterRegistrationsResource{}
+}
+
+// ClusterRegistrationsResource is the resource implementation.
+type ClusterRegistrationsResource struct {
+	client *KarporClient
+}
+
+// ClusterRegistrationsResourceModel is the resource model.
+type ClusterRegistrationsResourceModel struct {
+	Clusters    types.Map   `tfsdk:"clusters"`
+	Parallelism types.Int64 `tfsdk:"parallelism"`
+}
+
+// ClusterRegistrationsEntryModel is the model of a single cluster in the map.
+// An entry with a null id failed to register and is registered again on the
+// next apply.
+type ClusterRegistrationsEntryModel struct {
+	DisplayName types.String `tfsdk:"display_name"`
+	Description types.String `tfsdk:"description"`
+	Credentials types.String `tfsdk:"credentials"`
+	Id          types.String `tfsdk:"id"`
+
+	CredentialsWo      types.String `tfsdk:"credentials_wo"`
+	CredentialsVersion types.Int64  `tfsdk:"credentials_version"`
+	CredentialsHash    types.String `tfsdk:"credentials_hash"`
+
+	Labels    types.Map `tfsdk:"labels"`
+	LabelsAll types.Map `tfsdk:"labels_all"`
+}
+
+// clusterRegistrationsEntryAttrTypes are the attribute types of ClusterRegistrationsEntryModel.
+var clusterRegistrationsEntryAttrTypes = map[string]attr.Type{
+	"display_name":        types.StringType,
+	"description":         types.StringType,
+	"credentials":         types.StringType,
+	"id":                  types.StringType,
+	"credentials_wo":      types.StringType,
+	"credentials_version": types.Int64Type,
+	"credentials_hash":    types.StringType,
+	"labels":              types.MapType{ElemType: types.StringType},
+	"labels_all":          types.MapType{ElemType: types.StringType},
+}
+
+// Metadata returns the resource type name.
+func (r *ClusterRegistrationsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_registrations"
+}
+
+// Schema returns the resource schema.
+func (r *ClusterRegistrationsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage the registration of a set of clusters, clusters that fail to register are reported as warnings with a null `id` and registered again on the next apply",
+		Attributes: map[string]schema.Attribute{
+			"clusters": schema.MapNestedAttribute{
+				Required:    true,
+				Description: "Clusters to register, keyed by unique cluster name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"display_name": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Description: "Human-readable display name, by default it is the cluster name",
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseStateForUnknown(),
+							},
+						},
+						"description": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Description: "Human-readable description",
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseStateForUnknown(),
+							},
+						},
+						"credentials": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Kubeconfig content, changing it rotates the credentials in place. The value is stored in state, prefer `credentials_wo` on Terraform 1.11 and later",
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("credentials_wo")),
+								stringvalidator.PreferWriteOnlyAttribute(path.MatchRelative().AtParent().AtName("credentials_wo")),
+							},
+						},
+						"credentials_wo": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							WriteOnly:   true,
+							Description: "Kubeconfig content, never stored in plan or state. Requires Terraform 1.11 or later",
+						},
+						"credentials_version": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Version of `credentials_wo`, change it to rotate the credentials of the registered cluster",
+							Validators: []validator.Int64{
+								int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("credentials_wo")),
+							},
+						},
+						"credentials_hash": schema.StringAttribute{
+							Computed:    true,
+							Description: "SHA-256 hash of the registered kubeconfig",
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseStateForUnknown(),
+							},
+						},
+						"labels": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Labels of the cluster, merged over the provider `default_labels`",
+						},
+						"labels_all": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "Effective labels of the cluster, including the provider `default_labels` and excluding labels set by Karpor itself",
+						},
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Unique identifier, null while the cluster failed to register",
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseStateForUnknown(),
+							},
+						},
+					},
+				},
+			},
+			"parallelism": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(defaultClusterRegistrationsParallelism),
+				Description: "Maximum number of clusters processed concurrently, by default it is 5",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 50),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource.
+func (r *ClusterRegistrationsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ClusterRegistrationsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planClusters := map[string]ClusterRegistrationsEntryModel{}
+	resp.Diagnostics.Append(plan.Clusters.ElementsAs(ctx, &planClusters, false)...)
+	configClusters, configDiags := clusterRegistrationsConfig(ctx, req.Config)
+	resp.Diagnostics.Append(configDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Register all clusters, a failing cluster does not stop the others
+	var mu sync.Mutex
+	clusters := make(map[string]ClusterRegistrationsEntryModel, len(planClusters))
+	errs := forEachCluster(ctx, sortedClusterNames(planClusters), plan.Parallelism.ValueInt64(), func(name string) error {
+		entry, err := r.registerCluster(ctx, name, planClusters[name], configClusters[name])
+		mu.Lock()
+		clusters[name] = entry
+		mu.Unlock()
+		return err
+	})
+
+	// Errors would taint the resource and re-register every cluster, failed
+	// clusters are kept with a null id instead and registered on the next apply
+	addClusterWarnings(&resp.Diagnostics, "Failed to register cluster", errs)
+	var mapDiags diag.Diagnostics
+	plan.Clusters, mapDiags = clusterRegistrationsMap(ctx, clusters)
+	resp.Diagnostics.Append(mapDiags...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read reads the resource.
+func (r *ClusterRegistrationsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ClusterRegistrationsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stateClusters := map[string]ClusterRegistrationsEntryModel{}
+	resp.Diagnostics.Append(state.Clusters.ElementsAs(ctx, &stateClusters, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Refresh every registered cluster, dropping the ones removed outside of
+	// Terraform, clusters that failed to register are left to the next apply
+	names := []string{}
+	for _, name := range sortedClusterNames(stateClusters) {
+		if !stateClusters[name].Id.IsNull() {
+			names = append(names, name)
+		}
+	}
+	var mu sync.Mutex
+	errs := forEachCluster(ctx, names, state.Parallelism.ValueInt64(), func(name string) error {
+		remoteCluster, err := r.client.GetCluster(ctx, name)
+		mu.Lock()
+		defer mu.Unlock()
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "Cluster removed outside of Terraform", map[string]interface{}{"cluster_name": name})
+			delete(stateClusters, name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		entry := stateClusters[name]
+		entry.DisplayName = remoteCluster.DisplayName
+		entry.Description = remoteCluster.Description
+		entry.Id = remoteCluster.Id
+		entry.Labels = managedStringMap(remoteCluster.Labels, entry.Labels, r.client.DefaultLabels)
+		entry.LabelsAll = managedStringMap(remoteCluster.Labels, entry.LabelsAll, nil)
+		stateClusters[name] = entry
+		return nil
+	})
+	addClusterErrors(&resp.Diagnostics, "Error Reading Karpor Cluster", errs)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mapDiags diag.Diagnostics
+	state.Clusters, mapDiags = clusterRegistrationsMap(ctx, stateClusters)
+	resp.Diagnostics.Append(mapDiags...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource.
+func (r *ClusterRegistrationsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state ClusterRegistrationsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planClusters := map[string]ClusterRegistrationsEntryModel{}
+	stateClusters := map[string]ClusterRegistrationsEntryModel{}
+	resp.Diagnostics.Append(plan.Clusters.ElementsAs(ctx, &planClusters, false)...)
+	resp.Diagnostics.Append(state.Clusters.ElementsAs(ctx, &stateClusters, false)...)
+	configClusters, configDiags := clusterRegistrationsConfig(ctx, req.Config)
+	resp.Diagnostics.Append(configDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every cluster that is added, changed, removed or failed to register
+	names := []string{}
+	for name, planEntry := range planClusters {
+		stateEntry, ok := stateClusters[name]
+		if !ok || stateEntry.Id.IsNull() || !planEntry.DisplayName.Equal(stateEntry.DisplayName) ||
+			!planEntry.Description.Equal(stateEntry.Description) ||
+			!planEntry.Credentials.Equal(stateEntry.Credentials) ||
+			!planEntry.CredentialsVersion.Equal(stateEntry.CredentialsVersion) ||
+			!planEntry.Labels.Equal(stateEntry.Labels) ||
+			!planEntry.LabelsAll.Equal(stateEntry.LabelsAll) {
+			names = append(names, name)
+		}
+	}
+	for name := range stateClusters {
+		if _, ok := planClusters[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// Failed clusters keep their prior state, failed registrations are kept
+	// with a null id as on create
+	var mu sync.Mutex
+	registerErrs := map[string]error{}
+	clusters := make(map[string]ClusterRegistrationsEntryModel, len(stateClusters))
+	for name, entry := range stateClusters {
+		clusters[name] = entry
+	}
+	errs := forEachCluster(ctx, names, plan.Parallelism.ValueInt64(), func(name string) error {
+		planEntry, inPlan := planClusters[name]
+		stateEntry, inState := stateClusters[name]
+		var entry ClusterRegistrationsEntryModel
+		var err error
+		switch {
+		case !inPlan && stateEntry.Id.IsNull():
+			// Never registered, nothing to delete
+		case !inPlan:
+			err = r.deleteCluster(ctx, name)
+		case !inState || stateEntry.Id.IsNull():
+			entry, err = r.registerCluster(ctx, name, planEntry, configClusters[name])
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				clusters[name] = entry
+				registerErrs[name] = err
+				return nil
+			}
+		default:
+			entry, err = r.updateCluster(ctx, name, planEntry, stateEntry, configClusters[name])
+		}
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if inPlan {
+			clusters[name] = entry
+		} else {
+			delete(clusters, name)
+		}
+		return nil
+	})
+	addClusterWarnings(&resp.Diagnostics, "Failed to register cluster", registerErrs)
+	addClusterErrors(&resp.Diagnostics, "Failed to update cluster", errs)
+
+	var mapDiags diag.Diagnostics
+	plan.Clusters, mapDiags = clusterRegistrationsMap(ctx, clusters)
+	resp.Diagnostics.Append(mapDiags...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource.
+func (r *ClusterRegistrationsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ClusterRegistrationsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stateClusters := map[string]ClusterRegistrationsEntryModel{}
+	resp.Diagnostics.Append(state.Clusters.ElementsAs(ctx, &stateClusters, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Clusters that failed to register were never created by this resource
+	names := []string{}
+	for _, name := range sortedClusterNames(stateClusters) {
+		if !stateClusters[name].Id.IsNull() {
+			names = append(names, name)
+		}
+	}
+	errs := forEachCluster(ctx, names, state.Parallelism.ValueInt64(), func(name string) error {
+		return r.deleteCluster(ctx, name)
+	})
+	addClusterErrors(&resp.Diagnostics, "Error Deleting Karpor Cluster", errs)
+}
+
+// ModifyPlan plans the effective labels of every cluster, the registration
+// of clusters that failed to register and marks the credentials hash as
+// unknown when the credentials are rotated.
+func (r *ClusterRegistrationsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ClusterRegistrationsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Clusters.IsUnknown() {
+		return
+	}
+
+	planClusters := map[string]ClusterRegistrationsEntryModel{}
+	stateClusters := map[string]ClusterRegistrationsEntryModel{}
+	resp.Diagnostics.Append(plan.Clusters.ElementsAs(ctx, &planClusters, false)...)
+	if !req.State.Raw.IsNull() {
+		var state ClusterRegistrationsResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(state.Clusters.ElementsAs(ctx, &stateClusters, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for name, planEntry := range planClusters {
+		// The effective labels are known when the labels and the provider are
+		if !planEntry.Labels.IsUnknown() && r.client != nil {
+			planEntry.LabelsAll = effectiveLabels(r.client, &ClusterRegistrationResourceModel{Labels: planEntry.Labels})
+		}
+
+		stateEntry, ok := stateClusters[name]
+		switch {
+		case !ok:
+		case stateEntry.Id.IsNull():
+			planEntry.Id = types.StringUnknown()
+			planEntry.CredentialsHash = types.StringUnknown()
+		case !planEntry.Credentials.Equal(stateEntry.Credentials) || !planEntry.CredentialsVersion.Equal(stateEntry.CredentialsVersion):
+			planEntry.CredentialsHash = types.StringUnknown()
+		}
+		planClusters[name] = planEntry
+	}
+
+	clusters, diags := clusterRegistrationsMap(ctx, planClusters)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("clusters"), clusters)...)
+}
+
+// Configure configures the resource.
+func (r *ClusterRegistrationsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// registerCluster validates the credentials and registers a single cluster.
+// On failure it returns the planned entry with a null id.
+func (r *ClusterRegistrationsResource) registerCluster(ctx context.Context, name string, entry, configEntry ClusterRegistrationsEntryModel) (ClusterRegistrationsEntryModel, error) {
+	cluster := r.entryToCluster(name, entry)
+	failed := entry
+	failed.DisplayName = cluster.DisplayName
+	failed.Description = cluster.Description
+	failed.LabelsAll = cluster.LabelsAll
+	failed.Id = types.StringNull()
+	failed.CredentialsHash = types.StringNull()
+
+	kubeConfig := entry.kubeConfig(configEntry)
+	success, err := r.client.ValidateClusterConfig(ctx, kubeConfig)
+	if err != nil {
+		return failed, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	if !success {
+		return failed, fmt.Errorf("invalid kubeconfig")
+	}
+
+	uid, err := r.client.RegisterCluster(ctx, cluster, kubeConfig)
+	if err != nil {
+		return failed, err
+	}
+
+	entry.DisplayName = cluster.DisplayName
+	entry.Description = types.StringValue(cluster.Description.ValueString())
+	entry.Id = types.StringValue(uid)
+	entry.LabelsAll = cluster.LabelsAll
+	entry.CredentialsHash = hashCredentials(kubeConfig)
+	return entry, nil
+}
+
+// updateCluster updates a single cluster, rotating its credentials if they
+// or their version changed.
+func (r *ClusterRegistrationsResource) updateCluster(ctx context.Context, name string, planEntry, stateEntry, configEntry ClusterRegistrationsEntryModel) (ClusterRegistrationsEntryModel, error) {
+	kubeConfig := ""
+	planEntry.CredentialsHash = stateEntry.CredentialsHash
+	if !planEntry.Credentials.Equal(stateEntry.Credentials) || !planEntry.CredentialsVersion.Equal(stateEntry.CredentialsVersion) {
+		kubeConfig = planEntry.kubeConfig(configEntry)
+		success, err := r.client.ValidateClusterConfig(ctx, kubeConfig)
+		if err != nil {
+			return stateEntry, fmt.Errorf("invalid kubeconfig: %w", err)
+		}
+		if !success {
+			return stateEntry, fmt.Errorf("invalid kubeconfig")
+		}
+		planEntry.CredentialsHash = hashCredentials(kubeConfig)
+	}
+
+	cluster := r.entryToCluster(name, planEntry)
+	if cluster.DisplayName.IsUnknown() {
+		cluster.DisplayName = stateEntry.DisplayName
+	}
+	if cluster.Description.IsUnknown() {
+		cluster.Description = stateEntry.Description
+	}
+	if _, err := r.client.UpdateCluster(ctx, cluster, kubeConfig); err != nil {
+		return stateEntry, err
+	}
+
+	remoteCluster, err := r.client.GetCluster(ctx, name)
+	if err != nil {
+		return stateEntry, err
+	}
+	planEntry.DisplayName = remoteCluster.DisplayName
+	planEntry.Description = remoteCluster.Description
+	planEntry.Id = remoteCluster.Id
+	planEntry.LabelsAll = cluster.LabelsAll
+	return planEntry, nil
+}
+
+// deleteCluster deletes a single cluster, ignoring clusters that are already gone.
+func (r *ClusterRegistrationsResource) deleteCluster(ctx context.Context, name string) error {
+	_, err := r.client.DeleteCluster(ctx, &ClusterRegistrationResourceModel{ClusterName: types.StringValue(name)})
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// entryToCluster converts a map entry into the single cluster model used by
+// the client, with the provider default labels merged into its labels.
+func (r *ClusterRegistrationsResource) entryToCluster(name string, entry ClusterRegistrationsEntryModel) *ClusterRegistrationResourceModel {
+	cluster := &ClusterRegistrationResourceModel{
+		ClusterName: types.StringValue(name),
+		DisplayName: entry.DisplayName,
+		Description: entry.Description,
+		Labels:      entry.Labels,
+		LabelsAll:   entry.LabelsAll,
+		// Annotations are not managed by this resource, keep the remote ones
+		Annotations: types.MapUnknown(types.StringType),
+	}
+	if cluster.DisplayName.IsUnknown() {
+		cluster.DisplayName = types.StringNull()
+	}
+	if cluster.Description.IsUnknown() {
+		cluster.Description = types.StringNull()
+	}
+	if cluster.LabelsAll.IsUnknown() {
+		cluster.LabelsAll = effectiveLabels(r.client, cluster)
+	}
+	return cluster
+}
+
+// kubeConfig returns the credentials of the entry, read from the write-only
+// credentials_wo of its configuration when set.
+func (m ClusterRegistrationsEntryModel) kubeConfig(configEntry ClusterRegistrationsEntryModel) string {
+	if !configEntry.CredentialsWo.IsNull() {
+		return configEntry.CredentialsWo.ValueString()
+	}
+	return m.Credentials.ValueString()
+}
+
+// clusterRegistrationsConfig returns the configured clusters, which alone
+// hold the write-only credentials.
+func clusterRegistrationsConfig(ctx context.Context, config tfsdk.Config) (map[string]ClusterRegistrationsEntryModel, diag.Diagnostics) {
+	var clusters types.Map
+	diags := config.GetAttribute(ctx, path.Root("clusters"), &clusters)
+	if diags.HasError() {
+		return nil, diags
+	}
+	configClusters := map[string]ClusterRegistrationsEntryModel{}
+	diags.Append(clusters.ElementsAs(ctx, &configClusters, false)...)
+	return configClusters, diags
+}
+
+// clusterRegistrationsMap converts the clusters back into a Terraform map.
+func clusterRegistrationsMap(ctx context.Context, clusters map[string]ClusterRegistrationsEntryModel) (types.Map, diag.Diagnostics) {
+	return types.MapValueFrom(ctx, types.ObjectType{AttrTypes: clusterRegistrationsEntryAttrTypes}, clusters)
+}
+
+// forEachCluster calls fn for every cluster name with at most parallelism
+// calls in flight, and returns the errors keyed by cluster name.
+func forEachCluster(ctx context.Context, names []string, parallelism int64, fn func(name string) error) map[string]error {
+	if parallelism < 1 {
+		parallelism = defaultClusterRegistrationsParallelism
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := map[string]error{}
+	sem := make(chan struct{}, parallelism)
+	for _, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var err error
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			} else {
+				err = fn(name)
+			}
+			if err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+	return errs
+}
+
+// addClusterWarnings reports one warning per failed cluster.
+func addClusterWarnings(diags *diag.Diagnostics, summary string, errs map[string]error) {
+	for _, name := range sortedClusterNames(errs) {
+		diags.AddAttributeWarning(
+			path.Root("clusters").AtMapKey(name),
+			summary,
+			"Cluster "+name+": "+errs[name].Error()+"\n\nThe cluster is registered again on the next apply.",
+		)
+	}
+}
+
+// addClusterErrors reports one diagnostic per failed cluster.
+func addClusterErrors(diags *diag.Diagnostics, summary string, errs map[string]error) {
+	for _, name := range sortedClusterNames(errs) {
+		diags.AddAttributeError(
+			path.Root("clusters").AtMapKey(name),
+			summary,
+			"Cluster "+name+": "+errs[name].Error(),
+		)
+	}
+}
+
+// sortedClusterNames returns the keys of m in a stable order.
+func sortedClusterNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
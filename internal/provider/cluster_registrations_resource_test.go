@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccClusterRegistrations(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read
+			// Make sure you have a valid kubeconfig file (only one cluster) in your home directory
+			{
+				Config: providerConfig + `
+				resource "karpor_cluster_registrations" "test" {
+					parallelism = 2
+					clusters = {
+						"test-bulk-a" = {
+							credentials = file("~/config")
+						}
+						"test-bulk-b" = {
+							display_name = "test-bulk-b-display-name"
+							credentials  = file("~/config")
+						}
+					}
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registrations.test",
+						tfjsonpath.New("clusters").AtMapKey("test-bulk-a").AtMapKey("display_name"),
+						knownvalue.StringExact("test-bulk-a"),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registrations.test",
+						tfjsonpath.New("clusters").AtMapKey("test-bulk-b").AtMapKey("display_name"),
+						knownvalue.StringExact("test-bulk-b-display-name"),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registrations.test",
+						tfjsonpath.New("clusters").AtMapKey("test-bulk-a").AtMapKey("id"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Update one cluster, remove one and add one
+			{
+				Config: providerConfig + `
+				resource "karpor_cluster_registrations" "test" {
+					parallelism = 2
+					clusters = {
+						"test-bulk-a" = {
+							description = "test-bulk-a-description"
+							credentials = file("~/config")
+						}
+						"test-bulk-c" = {
+							credentials = file("~/config")
+						}
+					}
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registrations.test",
+						tfjsonpath.New("clusters").AtMapKey("test-bulk-a").AtMapKey("description"),
+						knownvalue.StringExact("test-bulk-a-description"),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_cluster_registrations.test",
+						tfjsonpath.New("clusters"),
+						knownvalue.MapSizeExact(2),
+					),
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// clusterRegistrationsValue returns a value of the cluster registrations
+// resource with the given entries, whose unset attributes are null.
+func clusterRegistrationsValue(ctx context.Context, schemaResp *fwresource.SchemaResponse, clusters map[string]map[string]tftypes.Value) tftypes.Value {
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	entryType := objectType.AttributeTypes["clusters"].(tftypes.Map).ElementType.(tftypes.Object)
+	entries := map[string]tftypes.Value{}
+	for name, attributes := range clusters {
+		values := map[string]tftypes.Value{}
+		for attribute, attributeType := range entryType.AttributeTypes {
+			values[attribute] = tftypes.NewValue(attributeType, nil)
+		}
+		for attribute, value := range attributes {
+			values[attribute] = value
+		}
+		entries[name] = tftypes.NewValue(entryType, values)
+	}
+	return tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"clusters":    tftypes.NewValue(objectType.AttributeTypes["clusters"], entries),
+		"parallelism": tftypes.NewValue(tftypes.Number, 2),
+	})
+}
+
+func TestClusterRegistrationsCreatePartialFailure(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/config/validate"):
+			_, _ = w.Write([]byte(`{"success": true}`))
+		case strings.HasSuffix(r.URL.Path, "/cluster/bad"):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{"success": true, "data": {"metadata": {"uid": "uid-good"}}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &ClusterRegistrationsResource{client: client}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	config := clusterRegistrationsValue(ctx, schemaResp, map[string]map[string]tftypes.Value{
+		"good": {"credentials_wo": tftypes.NewValue(tftypes.String, "good-kubeconfig")},
+		"bad":  {"credentials_wo": tftypes.NewValue(tftypes.String, "bad-kubeconfig")},
+	})
+	plan := clusterRegistrationsValue(ctx, schemaResp, map[string]map[string]tftypes.Value{
+		"good": {"id": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)},
+		"bad":  {"id": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)},
+	})
+	resp := &fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+	}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected no errors, which would taint the registered clusters, got %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 || !resp.Diagnostics.Warnings()[0].(diag.DiagnosticWithPath).Path().Equal(path.Root("clusters").AtMapKey("bad")) {
+		t.Errorf("expected a warning for the failed cluster, got %v", resp.Diagnostics)
+	}
+
+	var state ClusterRegistrationsResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	clusters := map[string]ClusterRegistrationsEntryModel{}
+	resp.Diagnostics.Append(state.Clusters.ElementsAs(ctx, &clusters, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if !clusters["good"].Id.Equal(types.StringValue("uid-good")) || !clusters["good"].CredentialsHash.Equal(hashCredentials("good-kubeconfig")) {
+		t.Errorf("expected the registered cluster in state, got %+v", clusters["good"])
+	}
+	if bad, ok := clusters["bad"]; !ok || !bad.Id.IsNull() {
+		t.Errorf("expected the failed cluster in state with a null id, got %+v", bad)
+	}
+	if !clusters["good"].CredentialsWo.IsNull() {
+		t.Error("expected the write-only credentials to stay out of state")
+	}
+
+	// The failed cluster is planned again
+	modifyResp := &fwresource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: resp.State.Raw}}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: resp.State.Raw},
+		State:  resp.State,
+	}, modifyResp)
+	var id, goodID types.String
+	modifyResp.Diagnostics.Append(modifyResp.Plan.GetAttribute(ctx, path.Root("clusters").AtMapKey("bad").AtName("id"), &id)...)
+	modifyResp.Diagnostics.Append(modifyResp.Plan.GetAttribute(ctx, path.Root("clusters").AtMapKey("good").AtName("id"), &goodID)...)
+	if modifyResp.Diagnostics.HasError() {
+		t.Fatal(modifyResp.Diagnostics)
+	}
+	if !id.IsUnknown() || goodID.ValueString() != "uid-good" {
+		t.Errorf("expected only the failed cluster to be planned again, got %s and %s", id, goodID)
+	}
+}
+
+func TestClusterRegistrationsDefaultLabels(t *testing.T) {
+	ctx := context.Background()
+	var labels map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/config/validate") {
+			_, _ = w.Write([]byte(`{"success": true}`))
+			return
+		}
+		payload := struct {
+			Labels map[string]string `json:"labels"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		labels = payload.Labels
+		_, _ = w.Write([]byte(`{"success": true, "data": {"metadata": {"uid": "uid-demo"}}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.DefaultLabels = map[string]string{"team": "platform", "env": "dev"}
+	r := &ClusterRegistrationsResource{client: client}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	entryLabels := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"env": tftypes.NewValue(tftypes.String, "prod"),
+	})
+	config := clusterRegistrationsValue(ctx, schemaResp, map[string]map[string]tftypes.Value{
+		"demo": {
+			"credentials_wo": tftypes.NewValue(tftypes.String, "demo-kubeconfig"),
+			"labels":         entryLabels,
+		},
+	})
+	plan := clusterRegistrationsValue(ctx, schemaResp, map[string]map[string]tftypes.Value{
+		"demo": {
+			"labels":     entryLabels,
+			"labels_all": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tftypes.UnknownValue),
+			"id":         tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		},
+	})
+
+	// The effective labels are planned
+	modifyResp := &fwresource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan}}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+		State:  tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(plan.Type(), nil)},
+	}, modifyResp)
+	var labelsAll types.Map
+	modifyResp.Diagnostics.Append(modifyResp.Plan.GetAttribute(ctx, path.Root("clusters").AtMapKey("demo").AtName("labels_all"), &labelsAll)...)
+	if modifyResp.Diagnostics.HasError() {
+		t.Fatal(modifyResp.Diagnostics)
+	}
+	want := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"team": types.StringValue("platform"),
+		"env":  types.StringValue("prod"),
+	})
+	if !labelsAll.Equal(want) {
+		t.Errorf("expected the default labels to be planned, got %s", labelsAll)
+	}
+
+	// The default labels are registered, labels of the cluster take precedence
+	resp := &fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config},
+		Plan:   modifyResp.Plan,
+	}, resp)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() > 0 {
+		t.Fatal(resp.Diagnostics)
+	}
+	if labels["team"] != "platform" || labels["env"] != "prod" {
+		t.Errorf("expected the default labels to be registered, got %v", labels)
+	}
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("clusters").AtMapKey("demo").AtName("labels_all"), &labelsAll)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if !labelsAll.Equal(want) {
+		t.Errorf("expected the effective labels in state, got %s", labelsAll)
+	}
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &CurrentIdentityDataSource{}
+	_ datasource.DataSourceWithConfigure = &CurrentIdentityDataSource{}
+)
+
+// NewCurrentIdentityDataSource returns a new datasource.DataSource.
+func NewCurrentIdentityDataSource() datasource.DataSource {
+	return &CurrentIdentityDataSource{}
+}
+
+// CurrentIdentityDataSource is the datasource implementation.
+type CurrentIdentityDataSource struct {
+	client *KarporClient
+}
+
+// CurrentIdentityDataSourceModel is the datasource model.
+type CurrentIdentityDataSourceModel struct {
+	Name        types.String      `tfsdk:"name"`
+	Kind        types.String      `tfsdk:"kind"`
+	Groups      types.List        `tfsdk:"groups"`
+	Permissions []PermissionModel `tfsdk:"permissions"`
+}
+
+// PermissionModel is the model of the verbs granted on a Karpor resource.
+type PermissionModel struct {
+	Resource types.String `tfsdk:"resource"`
+	Verbs    types.List   `tfsdk:"verbs"`
+	Clusters types.List   `tfsdk:"clusters"`
+}
+
+// Metadata returns the metadata for the datasource.
+func (d *CurrentIdentityDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_current_identity"
+}
+
+// Schema returns the schema for the datasource.
+func (d *CurrentIdentityDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Get who the provider's `api_key` authenticates as and what it may do",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the authenticated subject",
+			},
+			"kind": schema.StringAttribute{
+				Computed:    true,
+				Description: "Kind of the authenticated subject, `User` or `APIToken`",
+			},
+			"groups": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Groups the subject belongs to",
+			},
+			"permissions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Verbs granted to the subject by its role bindings",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"resource": schema.StringAttribute{
+							Computed:    true,
+							Description: "Karpor resource the verbs are granted on",
+						},
+						"verbs": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "Granted verbs",
+						},
+						"clusters": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "Clusters the verbs are granted on, null for all clusters",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read reads the datasource.
+func (d *CurrentIdentityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	identity, err := d.client.GetCurrentIdentity(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get current identity", err.Error())
+		return
+	}
+
+	state := CurrentIdentityDataSourceModel{
+		Name:        types.StringValue(identity.Name),
+		Kind:        stringOrNull(identity.Kind),
+		Groups:      stringListOrNull(identity.Groups),
+		Permissions: make([]PermissionModel, 0, len(identity.Permissions)),
+	}
+	for _, permission := range identity.Permissions {
+		state.Permissions = append(state.Permissions, PermissionModel{
+			Resource: types.StringValue(permission.Resource),
+			Verbs:    stringListOrNull(permission.Verbs),
+			Clusters: stringListOrNull(permission.Clusters),
+		})
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Configure configures the datasource.
+func (d *CurrentIdentityDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
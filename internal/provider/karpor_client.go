@@ -4,42 +4,188 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/time/rate"
 )
 
+// StatusError is returned when Karpor responds with a non-200 status code.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is a Karpor 404 response.
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether err is a Karpor 409 response or a failure
+// reporting that the object already exists or was modified concurrently.
+func IsConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusConflict {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "already exists") || strings.Contains(message, "the object has been modified")
+}
+
 // KarporClient is the Karpor client.
 type KarporClient struct {
 	Client      *http.Client
 	ApiEndpoint string
 	ApiKey      string
+
+	// DeletionProtection is the provider-level default of deletion_protection.
+	DeletionProtection bool
+	// DefaultLabels are merged into the labels of every registered cluster.
+	DefaultLabels map[string]string
+
+	// requests limits the number of in-flight requests, nil means unlimited.
+	requests chan struct{}
+	// limiter limits the request rate, nil means unlimited.
+	limiter *rate.Limiter
+	// extraHeaders are added to every request.
+	extraHeaders http.Header
+
+	clusterLocksMu sync.Mutex
+	// clusterLocks holds a buffered channel per cluster name, sending to it
+	// acquires the lock.
+	clusterLocks map[string]chan struct{}
+}
+
+// KarporClientOption configures optional behaviour of the Karpor client.
+type KarporClientOption func(*KarporClient)
+
+// WithMaxConcurrentRequests limits the number of requests sent to Karpor at the same time.
+func WithMaxConcurrentRequests(n int) KarporClientOption {
+	return func(c *KarporClient) {
+		if n > 0 {
+			c.requests = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithRateLimit limits the requests sent to Karpor to requestsPerSecond,
+// allowing bursts of up to burst requests.
+func WithRateLimit(requestsPerSecond float64, burst int) KarporClientOption {
+	return func(c *KarporClient) {
+		if requestsPerSecond > 0 {
+			c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), max(burst, 1))
+		}
+	}
+}
+
+// WithProxy sends requests through proxyURL, except to the hosts matched by
+// noProxy. Empty values fall back to the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+// environment variables.
+func WithProxy(proxyURL string, noProxy string) KarporClientOption {
+	return func(c *KarporClient) {
+		transport, ok := c.Client.Transport.(*http.Transport)
+		if !ok || (proxyURL == "" && noProxy == "") {
+			return
+		}
+		config := httpproxy.FromEnvironment()
+		if proxyURL != "" {
+			config.HTTPProxy = proxyURL
+			config.HTTPSProxy = proxyURL
+		}
+		if noProxy != "" {
+			config.NoProxy = noProxy
+		}
+		proxyFunc := config.ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+}
+
+// WithExtraHeaders adds headers to every request sent to Karpor, e.g. for an
+// API gateway in front of it.
+func WithExtraHeaders(headers map[string]string) KarporClientOption {
+	return func(c *KarporClient) {
+		if len(headers) == 0 {
+			return
+		}
+		c.extraHeaders = http.Header{}
+		for name, value := range headers {
+			c.extraHeaders.Set(name, value)
+		}
+	}
 }
 
 // NewKarporClient creates a new Karpor client.
-func NewKarporClient(endpoint string, key string, skipTlsVerify bool) (*KarporClient, error) {
-	return &KarporClient{
+func NewKarporClient(endpoint string, key string, skipTlsVerify bool, opts ...KarporClientOption) (*KarporClient, error) {
+	client := &KarporClient{
 		Client: &http.Client{
 			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
 				TLSClientConfig: &tls.Config{
 					InsecureSkipVerify: skipTlsVerify,
 				},
 			},
 			Timeout: 10 * time.Second,
 		},
-		ApiEndpoint: endpoint,
-		ApiKey:      key,
-	}, nil
+		ApiEndpoint:  endpoint,
+		ApiKey:       key,
+		clusterLocks: map[string]chan struct{}{},
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client, nil
 }
 
-// ValidateClusterConfig validates the cluster config.
-func (c *KarporClient) ValidateClusterConfig(ctx context.Context, cluster *ClusterRegistrationResourceModel) (bool, error) {
+// lockCluster serializes mutating operations on the same cluster name and
+// returns the function releasing the lock. It gives up when ctx is done, so
+// a hung operation does not block the others past their timeout.
+func (c *KarporClient) lockCluster(ctx context.Context, clusterName string) (func(), error) {
+	c.clusterLocksMu.Lock()
+	lock, ok := c.clusterLocks[clusterName]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		c.clusterLocks[clusterName] = lock
+	}
+	c.clusterLocksMu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for another operation on cluster %s: %w", clusterName, ctx.Err())
+	}
+}
+
+// ValidateClusterConfig validates the cluster kubeconfig.
+func (c *KarporClient) ValidateClusterConfig(ctx context.Context, kubeConfig string) (bool, error) {
 	payloadData := map[string]string{
-		"kubeConfig": cluster.Credentials.ValueString(),
+		"kubeConfig": kubeConfig,
 	}
 	payloadBytes, err := json.Marshal(payloadData)
 	if err != nil {
@@ -47,7 +193,7 @@ func (c *KarporClient) ValidateClusterConfig(ctx context.Context, cluster *Clust
 	}
 	payload := strings.NewReader(string(payloadBytes))
 
-	req, err := http.NewRequest("POST", c.ApiEndpoint+"/rest-api/v1/cluster/config/validate", payload)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.ApiEndpoint+"/rest-api/v1/cluster/config/validate", payload)
 	if err != nil {
 		return false, err
 	}
@@ -66,15 +212,23 @@ func (c *KarporClient) ValidateClusterConfig(ctx context.Context, cluster *Clust
 	return success, nil
 }
 
-// RegisterCluster registers a new cluster.
-func (c *KarporClient) RegisterCluster(ctx context.Context, cluster *ClusterRegistrationResourceModel) (string, error) {
+// RegisterCluster registers a new cluster with the given kubeconfig.
+func (c *KarporClient) RegisterCluster(ctx context.Context, cluster *ClusterRegistrationResourceModel, kubeConfig string) (string, error) {
+	unlock, err := c.lockCluster(ctx, cluster.ClusterName.ValueString())
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
 	if cluster.DisplayName.IsNull() {
 		cluster.DisplayName = types.StringValue(cluster.ClusterName.ValueString())
 	}
-	payloadData := map[string]string{
+	payloadData := map[string]interface{}{
 		"displayName": cluster.DisplayName.ValueString(),
 		"description": cluster.Description.ValueString(),
-		"kubeConfig":  cluster.Credentials.ValueString(),
+		"kubeConfig":  kubeConfig,
+		"labels":      clusterLabels(cluster),
+		"annotations": stringMapValue(cluster.Annotations),
 	}
 	payloadBytes, err := json.Marshal(payloadData)
 	if err != nil {
@@ -82,7 +236,7 @@ func (c *KarporClient) RegisterCluster(ctx context.Context, cluster *ClusterRegi
 	}
 	payload := strings.NewReader(string(payloadBytes))
 
-	req, err := http.NewRequest("POST", c.ApiEndpoint+"/rest-api/v1/cluster/"+cluster.ClusterName.ValueString(), payload)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.ApiEndpoint+"/rest-api/v1/cluster/"+cluster.ClusterName.ValueString(), payload)
 	if err != nil {
 		return "", err
 	}
@@ -121,7 +275,66 @@ func (c *KarporClient) RegisterCluster(ctx context.Context, cluster *ClusterRegi
 
 // GetCluster gets a cluster.
 func (c *KarporClient) GetCluster(ctx context.Context, clusterName string) (*ClusterRegistrationResourceModel, error) {
-	req, err := http.NewRequest("GET", c.ApiEndpoint+"/rest-api/v1/cluster/"+clusterName, nil)
+	clusterData, err := c.getClusterObject(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return clusterFromObject(clusterData)
+}
+
+// ClusterStatus is the connection and sync status Karpor reports for a cluster.
+type ClusterStatus struct {
+	Phase   string
+	Healthy bool
+	Synced  bool
+	Message string
+}
+
+// String returns a human-readable summary of the status.
+func (s *ClusterStatus) String() string {
+	return fmt.Sprintf("phase=%q, healthy=%t, synced=%t, message=%q", s.Phase, s.Healthy, s.Synced, s.Message)
+}
+
+// GetClusterStatus gets the status of a cluster.
+func (c *KarporClient) GetClusterStatus(ctx context.Context, clusterName string) (*ClusterStatus, error) {
+	clusterData, err := c.getClusterObject(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cluster Karpor has not reconciled yet has no status
+	status, _ := clusterData["status"].(map[string]interface{})
+	phase, _ := status["phase"].(string)
+	healthy, _ := status["healthy"].(bool)
+	synced, _ := status["synced"].(bool)
+	message, _ := status["message"].(string)
+	return &ClusterStatus{
+		Phase:   phase,
+		Healthy: healthy,
+		Synced:  synced,
+		Message: message,
+	}, nil
+}
+
+// GetClusterEndpoint gets the API server address Karpor uses to access a cluster.
+func (c *KarporClient) GetClusterEndpoint(ctx context.Context, clusterName string) (string, error) {
+	clusterData, err := c.getClusterObject(ctx, clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	spec, _ := clusterData["spec"].(map[string]interface{})
+	access, _ := spec["access"].(map[string]interface{})
+	endpoint, ok := access["endpoint"].(string)
+	if !ok {
+		return "", fmt.Errorf("missing or invalid access endpoint field in response")
+	}
+	return endpoint, nil
+}
+
+// getClusterObject gets the raw Karpor cluster object.
+func (c *KarporClient) getClusterObject(ctx context.Context, clusterName string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.ApiEndpoint+"/rest-api/v1/cluster/"+clusterName, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +358,75 @@ func (c *KarporClient) GetCluster(ctx context.Context, clusterName string) (*Clu
 	if !ok {
 		return nil, fmt.Errorf("missing data field in response")
 	}
+	return clusterData, nil
+}
+
+// ListClusters lists all clusters registered in Karpor.
+func (c *KarporClient) ListClusters(ctx context.Context) ([]*ClusterRegistrationResourceModel, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.ApiEndpoint+"/rest-api/v1/clusters", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{}
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return nil, err
+	}
+	success, _ := data["success"].(bool)
+	if !success {
+		message, _ := data["message"].(string)
+		return nil, fmt.Errorf("%s", message)
+	}
+
+	// Depending on the Karpor version the clusters are returned either as a
+	// plain list or as a paginated object
+	var items []interface{}
+	switch listData := data["data"].(type) {
+	case []interface{}:
+		items = listData
+	case map[string]interface{}:
+		items, _ = listData["items"].([]interface{})
+	default:
+		return nil, fmt.Errorf("missing data field in response")
+	}
+
+	clusters := make([]*ClusterRegistrationResourceModel, 0, len(items))
+	for _, item := range items {
+		clusterData, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid cluster item in response")
+		}
+		cluster, err := clusterFromObject(clusterData)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// FindClusterByUID looks up a cluster by its Karpor UID.
+func (c *KarporClient) FindClusterByUID(ctx context.Context, uid string) (*ClusterRegistrationResourceModel, error) {
+	clusters, err := c.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		if cluster.Id.ValueString() == uid {
+			return cluster, nil
+		}
+	}
+	return nil, fmt.Errorf("no cluster found with uid %s", uid)
+}
+
+// clusterFromObject converts a Karpor cluster object into the resource model.
+func clusterFromObject(clusterData map[string]interface{}) (*ClusterRegistrationResourceModel, error) {
 	metadata, ok := clusterData["metadata"].(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("missing metadata field in response")
@@ -174,20 +456,212 @@ func (c *KarporClient) GetCluster(ctx context.Context, clusterName string) (*Clu
 		return nil, fmt.Errorf("missing or invalid description field in response")
 	}
 
+	labels, err := stringMapFromObject(metadata, "labels")
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := stringMapFromObject(metadata, "annotations")
+	if err != nil {
+		return nil, err
+	}
+
 	remoteCluster := ClusterRegistrationResourceModel{
-		Id:          types.StringValue(uid),
-		ClusterName: types.StringValue(returnedClusterName),
-		DisplayName: types.StringValue(displayName),
-		Description: types.StringValue(description),
+		Id:              types.StringValue(uid),
+		ClusterName:     types.StringValue(returnedClusterName),
+		DisplayName:     types.StringValue(displayName),
+		Description:     types.StringValue(description),
+		Labels:          labels,
+		Annotations:     annotations,
+		CreatedAt:       types.StringNull(),
+		LastUpdated:     types.StringNull(),
+		ResourceVersion: types.StringNull(),
+		Generation:      types.Int64Null(),
+	}
+
+	if resourceVersion, ok := metadata["resourceVersion"].(string); ok {
+		remoteCluster.ResourceVersion = types.StringValue(resourceVersion)
+	}
+	// JSON numbers are decoded as float64
+	if generation, ok := metadata["generation"].(float64); ok {
+		remoteCluster.Generation = types.Int64Value(int64(generation))
+	}
+	createdAt, lastUpdated, err := metadataTimestamps(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if !createdAt.IsZero() {
+		remoteCluster.CreatedAt = types.StringValue(createdAt.Format(time.RFC3339))
+	}
+	if !lastUpdated.IsZero() {
+		remoteCluster.LastUpdated = types.StringValue(lastUpdated.Format(time.RFC3339))
 	}
 	return &remoteCluster, nil
 }
 
-// UpdateCluster updates a cluster.
-func (c *KarporClient) UpdateCluster(ctx context.Context, cluster *ClusterRegistrationResourceModel) (bool, error) {
-	payloadData := map[string]string{
+// metadataTimestamps returns the creation time of a Karpor object and the
+// time it was last modified, which is the newest managedFields entry or the
+// creation time if there is none.
+func metadataTimestamps(metadata map[string]interface{}) (time.Time, time.Time, error) {
+	var createdAt time.Time
+	if raw, ok := metadata["creationTimestamp"].(string); ok && raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid creationTimestamp field in response: %w", err)
+		}
+		createdAt = t
+	}
+
+	lastUpdated := createdAt
+	managedFields, _ := metadata["managedFields"].([]interface{})
+	for _, entry := range managedFields {
+		fields, _ := entry.(map[string]interface{})
+		raw, ok := fields["time"].(string)
+		if !ok || raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid managedFields time in response: %w", err)
+		}
+		if t.After(lastUpdated) {
+			lastUpdated = t
+		}
+	}
+	return createdAt, lastUpdated, nil
+}
+
+// clusterLabels returns the labels sent to Karpor for a cluster: its
+// labels_all, which include the provider default labels, when known and its
+// labels otherwise.
+func clusterLabels(cluster *ClusterRegistrationResourceModel) map[string]string {
+	if !cluster.LabelsAll.IsNull() && !cluster.LabelsAll.IsUnknown() {
+		return stringMapValue(cluster.LabelsAll)
+	}
+	return stringMapValue(cluster.Labels)
+}
+
+// withSystemMetadata returns the managed labels or annotations of a cluster
+// together with the system keys of its remote ones, managed values win.
+func withSystemMetadata(remote types.Map, managed map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range stringMapValue(remote) {
+		if isSystemMetadataKey(k) {
+			result[k] = v
+		}
+	}
+	for k, v := range managed {
+		result[k] = v
+	}
+	return result
+}
+
+// stringMapValue converts a map of strings into a Go map, null and unknown
+// maps are empty.
+func stringMapValue(m types.Map) map[string]string {
+	result := map[string]string{}
+	for k, v := range m.Elements() {
+		if s, ok := v.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			result[k] = s.ValueString()
+		}
+	}
+	return result
+}
+
+// stringMapOrNull converts a Go map into a map of strings, null if it is empty.
+func stringMapOrNull(m map[string]string) types.Map {
+	if len(m) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	elements := make(map[string]attr.Value, len(m))
+	for k, v := range m {
+		elements[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// stringListValue converts a list of strings into a Go slice, null and
+// unknown lists are empty.
+func stringListValue(l types.List) []string {
+	var result []string
+	for _, v := range l.Elements() {
+		if s, ok := v.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			result = append(result, s.ValueString())
+		}
+	}
+	return result
+}
+
+// stringListOrNull converts a Go slice into a list of strings, null if it is empty.
+func stringListOrNull(l []string) types.List {
+	if len(l) == 0 {
+		return types.ListNull(types.StringType)
+	}
+	elements := make([]attr.Value, 0, len(l))
+	for _, v := range l {
+		elements = append(elements, types.StringValue(v))
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// stringOrNull converts a Go string into a string, null if it is empty.
+func stringOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// stringMapFromObject reads an optional map of strings from a Karpor object,
+// returning a null map when it is missing or empty.
+func stringMapFromObject(object map[string]interface{}, key string) (types.Map, error) {
+	raw, ok := object[key].(map[string]interface{})
+	if !ok || len(raw) == 0 {
+		return types.MapNull(types.StringType), nil
+	}
+	elements := make(map[string]attr.Value, len(raw))
+	for k, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			return types.MapNull(types.StringType), fmt.Errorf("invalid %s field in response", key)
+		}
+		elements[k] = types.StringValue(s)
+	}
+	return types.MapValueMust(types.StringType, elements), nil
+}
+
+// UpdateCluster updates a cluster. A non-empty kubeConfig rotates the
+// credentials Karpor uses to access the cluster. A known resource version
+// makes Karpor reject the update if the cluster was modified since. Unknown
+// annotations leave the remote ones untouched.
+func (c *KarporClient) UpdateCluster(ctx context.Context, cluster *ClusterRegistrationResourceModel, kubeConfig string) (bool, error) {
+	unlock, err := c.lockCluster(ctx, cluster.ClusterName.ValueString())
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	// Keep the system labels and annotations set by Karpor, the resource
+	// hides them and must not remove them
+	remote, err := c.GetCluster(ctx, cluster.ClusterName.ValueString())
+	if err != nil {
+		return false, err
+	}
+	annotations := stringMapValue(remote.Annotations)
+	if !cluster.Annotations.IsUnknown() {
+		annotations = withSystemMetadata(remote.Annotations, stringMapValue(cluster.Annotations))
+	}
+
+	payloadData := map[string]interface{}{
 		"displayName": cluster.DisplayName.ValueString(),
 		"description": cluster.Description.ValueString(),
+		"labels":      withSystemMetadata(remote.Labels, clusterLabels(cluster)),
+		"annotations": annotations,
+	}
+	if kubeConfig != "" {
+		payloadData["kubeConfig"] = kubeConfig
+	}
+	if resourceVersion := cluster.ResourceVersion.ValueString(); resourceVersion != "" {
+		payloadData["resourceVersion"] = resourceVersion
 	}
 	payloadBytes, err := json.Marshal(payloadData)
 	if err != nil {
@@ -195,7 +669,7 @@ func (c *KarporClient) UpdateCluster(ctx context.Context, cluster *ClusterRegist
 	}
 	payload := strings.NewReader(string(payloadBytes))
 
-	req, err := http.NewRequest("PUT", c.ApiEndpoint+"/rest-api/v1/cluster/"+cluster.ClusterName.ValueString(), payload)
+	req, err := http.NewRequestWithContext(ctx, "PUT", c.ApiEndpoint+"/rest-api/v1/cluster/"+cluster.ClusterName.ValueString(), payload)
 	if err != nil {
 		return false, err
 	}
@@ -220,7 +694,13 @@ func (c *KarporClient) UpdateCluster(ctx context.Context, cluster *ClusterRegist
 
 // DeleteCluster deletes a cluster.
 func (c *KarporClient) DeleteCluster(ctx context.Context, cluster *ClusterRegistrationResourceModel) (bool, error) {
-	req, err := http.NewRequest("DELETE", c.ApiEndpoint+"/rest-api/v1/cluster/"+cluster.ClusterName.ValueString(), nil)
+	unlock, err := c.lockCluster(ctx, cluster.ClusterName.ValueString())
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.ApiEndpoint+"/rest-api/v1/cluster/"+cluster.ClusterName.ValueString(), nil)
 	if err != nil {
 		return false, err
 	}
@@ -243,14 +723,195 @@ func (c *KarporClient) DeleteCluster(ctx context.Context, cluster *ClusterRegist
 	return true, nil
 }
 
-func (c *KarporClient) doRequest(req *http.Request) ([]byte, error) {
+// ClusterKubeConfig is a short-lived kubeconfig issued by Karpor for a cluster.
+type ClusterKubeConfig struct {
+	KubeConfig               string
+	Server                   string
+	CertificateAuthorityData string
+	Token                    string
+	ExpirationTimestamp      string
+}
+
+// GetClusterKubeConfig requests a short-lived kubeconfig that reaches the
+// cluster through the Karpor proxy.
+func (c *KarporClient) GetClusterKubeConfig(ctx context.Context, clusterName string, expirationSeconds int64) (*ClusterKubeConfig, error) {
+	payloadData := map[string]int64{
+		"expirationSeconds": expirationSeconds,
+	}
+	payloadBytes, err := json.Marshal(payloadData)
+	if err != nil {
+		return nil, err
+	}
+	payload := strings.NewReader(string(payloadBytes))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.ApiEndpoint+"/rest-api/v1/cluster/"+url.PathEscape(clusterName)+"/kubeconfig", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{}
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return nil, err
+	}
+	success, _ := data["success"].(bool)
+	if !success {
+		message, _ := data["message"].(string)
+		return nil, fmt.Errorf("%s", message)
+	}
+	kubeConfigData, ok := data["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("missing data field in response")
+	}
+
+	kubeConfig, ok := kubeConfigData["kubeConfig"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid kubeConfig field in response")
+	}
+	server, ok := kubeConfigData["server"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid server field in response")
+	}
+	token, ok := kubeConfigData["token"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid token field in response")
+	}
+	caData, _ := kubeConfigData["certificateAuthorityData"].(string)
+	expirationTimestamp, _ := kubeConfigData["expirationTimestamp"].(string)
+
+	return &ClusterKubeConfig{
+		KubeConfig:               kubeConfig,
+		Server:                   server,
+		CertificateAuthorityData: caData,
+		Token:                    token,
+		ExpirationTimestamp:      expirationTimestamp,
+	}, nil
+}
+
+// ObjectMeta is the metadata of a Karpor object.
+type ObjectMeta struct {
+	Name            string `json:"name"`
+	UID             string `json:"uid"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// doJSON sends payload as JSON to the Karpor API path, checks the response
+// envelope and decodes its data into out. A nil payload sends no body and a
+// nil out ignores the data.
+func (c *KarporClient) doJSON(ctx context.Context, method string, apiPath string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = strings.NewReader(string(payloadBytes))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.ApiEndpoint+apiPath, body)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return err
+	}
+	if !envelope.Success {
+		return fmt.Errorf("%s", envelope.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return fmt.Errorf("missing data field in response")
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// doRequest sends an authenticated request to Karpor and returns the body of
+// a 200 response, logging it to the karpor_http subsystem and tracing it in
+// a span propagated to Karpor.
+func (c *KarporClient) doRequest(req *http.Request) (_ []byte, err error) {
+	ctx, span := tracer().Start(req.Context(), req.Method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLFull(req.URL.Redacted()),
+		semconv.ServerAddress(req.URL.Hostname()),
+	))
+	defer func() {
+		// Status errors are recorded without their body, which may hold secrets.
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			span.SetStatus(codes.Error, http.StatusText(statusErr.StatusCode))
+		} else if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	req = req.WithContext(ctx)
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if c.requests != nil {
+		select {
+		case c.requests <- struct{}{}:
+			defer func() { <-c.requests }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
 	token := c.ApiKey
 
+	for name, values := range c.extraHeaders {
+		req.Header[name] = values
+	}
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
 
+	requestID := newRequestID()
+	if requestID != "" {
+		req.Header.Set("X-Request-Id", requestID)
+	}
+	traceContext.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	var reqBody []byte
+	if req.GetBody != nil {
+		if bodyReader, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(bodyReader)
+		}
+	}
+
+	logCtx := c.httpLogContext(ctx)
+	fields := map[string]interface{}{
+		"method":     req.Method,
+		"url":        req.URL.Redacted(),
+		"request_id": requestID,
+	}
+
+	start := time.Now()
 	res, err := c.Client.Do(req)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
 	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(logCtx, httpLogSubsystem, "Karpor request failed", fields)
 		return nil, err
 	}
 	defer res.Body.Close()
@@ -260,8 +921,24 @@ func (c *KarporClient) doRequest(req *http.Request) ([]byte, error) {
 		return nil, err
 	}
 
+	if id := res.Header.Get("X-Request-Id"); id != "" {
+		requestID = id
+		fields["request_id"] = id
+	}
+	fields["status"] = res.StatusCode
+	span.SetAttributes(
+		semconv.HTTPResponseStatusCode(res.StatusCode),
+		attribute.String("karpor.request_id", requestID),
+	)
+	tflog.SubsystemDebug(logCtx, httpLogSubsystem, "Karpor request", fields)
+	tflog.SubsystemTrace(logCtx, httpLogSubsystem, "Karpor request bodies", map[string]interface{}{
+		"request_id":    requestID,
+		"request_body":  redactBody(reqBody),
+		"response_body": redactBody(body),
+	})
+
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+		return nil, &StatusError{StatusCode: res.StatusCode, Body: body}
 	}
 
 	return body, err
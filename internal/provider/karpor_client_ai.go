@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+)
+
+// AIBackend is the LLM backend Karpor uses for natural-language search and
+// AI diagnosis. The auth token is write-only and never returned by Karpor.
+type AIBackend struct {
+	Backend     string   `json:"backend"`
+	BaseURL     string   `json:"baseUrl,omitempty"`
+	Model       string   `json:"model,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"topP,omitempty"`
+	ProxyURL    string   `json:"proxyUrl,omitempty"`
+	NoProxy     string   `json:"noProxy,omitempty"`
+	AuthToken   string   `json:"authToken,omitempty"`
+}
+
+// AIStatus reports whether the AI features of Karpor are enabled.
+type AIStatus struct {
+	Enabled bool   `json:"enabled"`
+	Backend string `json:"backend"`
+	Model   string `json:"model"`
+}
+
+// GetAIBackend gets the AI backend configuration.
+func (c *KarporClient) GetAIBackend(ctx context.Context) (*AIBackend, error) {
+	backend := &AIBackend{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/ai/backend", nil, backend); err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+// UpdateAIBackend replaces the AI backend configuration. An empty auth token
+// keeps the token currently configured.
+func (c *KarporClient) UpdateAIBackend(ctx context.Context, backend *AIBackend) (*AIBackend, error) {
+	updated := &AIBackend{}
+	if err := c.doJSON(ctx, http.MethodPut, "/rest-api/v1/ai/backend", backend, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteAIBackend removes the AI backend configuration, disabling the AI
+// features.
+func (c *KarporClient) DeleteAIBackend(ctx context.Context) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest-api/v1/ai/backend", nil, nil)
+}
+
+// GetAIStatus gets the status of the AI features.
+func (c *KarporClient) GetAIStatus(ctx context.Context) (*AIStatus, error) {
+	status := &AIStatus{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/ai/status", nil, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// AuditPolicy is a Karpor policy tuning the scanner checks behind insight
+// scores.
+type AuditPolicy struct {
+	Metadata ObjectMeta      `json:"metadata"`
+	Spec     AuditPolicySpec `json:"spec"`
+}
+
+// AuditPolicySpec is the desired state of an audit policy.
+type AuditPolicySpec struct {
+	Clusters    []string            `json:"clusters,omitempty"`
+	Namespaces  []string            `json:"namespaces,omitempty"`
+	MinSeverity string              `json:"minSeverity,omitempty"`
+	Rules       []AuditRuleOverride `json:"rules,omitempty"`
+	Exemptions  []AuditExemption    `json:"exemptions,omitempty"`
+}
+
+// AuditRuleOverride enables, disables or changes the severity of a scanner
+// rule.
+type AuditRuleOverride struct {
+	ID       string `json:"id"`
+	Enabled  *bool  `json:"enabled,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// AuditExemption exempts the objects carrying all the labels from some or all
+// scanner rules.
+type AuditExemption struct {
+	Labels map[string]string `json:"labels"`
+	Rules  []string          `json:"rules,omitempty"`
+	Reason string            `json:"reason,omitempty"`
+}
+
+// ScannerRule is a check of the Karpor scanner.
+type ScannerRule struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Scanner     string `json:"scanner"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+}
+
+// CreateAuditPolicy creates an audit policy.
+func (c *KarporClient) CreateAuditPolicy(ctx context.Context, name string, spec *AuditPolicySpec) (*AuditPolicy, error) {
+	policy := &AuditPolicy{}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest-api/v1/audit-policy/"+url.PathEscape(name), spec, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetAuditPolicy gets an audit policy.
+func (c *KarporClient) GetAuditPolicy(ctx context.Context, name string) (*AuditPolicy, error) {
+	policy := &AuditPolicy{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/audit-policy/"+url.PathEscape(name), nil, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdateAuditPolicy replaces the spec of an audit policy.
+func (c *KarporClient) UpdateAuditPolicy(ctx context.Context, name string, spec *AuditPolicySpec) (*AuditPolicy, error) {
+	policy := &AuditPolicy{}
+	if err := c.doJSON(ctx, http.MethodPut, "/rest-api/v1/audit-policy/"+url.PathEscape(name), spec, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeleteAuditPolicy deletes an audit policy.
+func (c *KarporClient) DeleteAuditPolicy(ctx context.Context, name string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest-api/v1/audit-policy/"+url.PathEscape(name), nil, nil)
+}
+
+// ListScannerRules lists the checks of the Karpor scanner.
+func (c *KarporClient) ListScannerRules(ctx context.Context) ([]ScannerRule, error) {
+	var rules []ScannerRule
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/insight/scanner-rules", nil, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
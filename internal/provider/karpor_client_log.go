@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// httpLogSubsystem is the tflog subsystem of the Karpor requests, its
+	// level is set with TF_LOG_PROVIDER_KARPOR_HTTP.
+	httpLogSubsystem = "karpor_http"
+	// redactedValue replaces secrets in logged bodies.
+	redactedValue = "***REDACTED***"
+)
+
+// bearerPattern matches bearer credentials that may appear in error messages.
+var bearerPattern = regexp.MustCompile(`(?i)bearer\s+[^\s"']+`)
+
+// sensitiveKeyParts are the parts of JSON keys whose values are redacted,
+// matched case-insensitively and ignoring "-" and "_".
+var sensitiveKeyParts = []string{
+	"authorization",
+	"credential",
+	"kubeconfig",
+	"password",
+	"secret",
+	"token",
+	"apikey",
+	"keydata",
+	"privatekey",
+}
+
+// httpLogContext returns ctx with the karpor_http subsystem, masking the
+// credentials of the client wherever they would appear.
+func (c *KarporClient) httpLogContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, httpLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_KARPOR_HTTP"))
+	secrets := []string{}
+	if c.ApiKey != "" {
+		secrets = append(secrets, c.ApiKey)
+	}
+	for _, values := range c.extraHeaders {
+		for _, value := range values {
+			if value != "" {
+				secrets = append(secrets, value)
+			}
+		}
+	}
+	ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, httpLogSubsystem, secrets...)
+	ctx = tflog.SubsystemMaskMessageStrings(ctx, httpLogSubsystem, secrets...)
+	return tflog.SubsystemMaskAllFieldValuesRegexes(ctx, httpLogSubsystem, bearerPattern)
+}
+
+// newRequestID returns a random ID correlating a request with Karpor logs.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// redactBody returns a JSON body with the values of sensitive keys replaced.
+// Bodies that are not JSON are never logged as they cannot be redacted.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("<%d bytes, not JSON>", len(body))
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+	return string(redacted)
+}
+
+// redactValue replaces the values of sensitive keys in a decoded JSON value.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// isSensitiveKey reports whether the value of a JSON key may hold a secret.
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL returns a URL with its password, if any, replaced.
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	return parsed.Redacted()
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := map[string]string{
+		``: ``,
+		`{"spec": {"kubeConfig": "apiVersion: v1", "displayName": "demo"}}`:   `{"spec":{"displayName":"demo","kubeConfig":"***REDACTED***"}}`,
+		`{"data": [{"token": "abc", "name": "ci"}], "authToken": "def"}`:      `{"authToken":"***REDACTED***","data":[{"name":"ci","token":"***REDACTED***"}]}`,
+		`{"users": [{"user": {"client-key-data": "a2V5", "username": "u"}}]}`: `{"users":[{"user":{"client-key-data":"***REDACTED***","username":"u"}}]}`,
+		`token: abc`: `<10 bytes, not JSON>`,
+	}
+	for body, expected := range tests {
+		if got := redactBody([]byte(body)); got != expected {
+			t.Errorf("redactBody(%s) = %s, expected %s", body, got, expected)
+		}
+	}
+}
+
+func TestKarporClientRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Request-Id") == "" {
+			t.Error("expected a request ID")
+		}
+		w.Header().Set("X-Request-Id", "server-id")
+		_, _ = w.Write([]byte(`{"success": true, "data": {"token": "signed-token", "message": "Bearer signed-token"}}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client, err := NewKarporClient(server.URL, "super-secret-key", false,
+		WithExtraHeaders(map[string]string{"X-Gateway-Key": "gateway-secret"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.doJSON(ctx, http.MethodPost, "/rest-api/v1/api-token/ci/secret", map[string]string{"kubeConfig": "users: []"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a debug and a trace entry, got %v", entries)
+	}
+	request := entries[0]
+	if request["@module"] != "provider."+httpLogSubsystem || request["method"] != http.MethodPost ||
+		request["status"] != float64(http.StatusOK) || request["request_id"] != "server-id" {
+		t.Errorf("unexpected request entry %v", request)
+	}
+	if _, ok := request["duration_ms"]; !ok {
+		t.Errorf("expected the request duration, got %v", request)
+	}
+	for _, secret := range []string{"super-secret-key", "gateway-secret", "signed-token", "users: []"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("expected %q to be redacted from the logs:\n%s", secret, output.String())
+		}
+	}
+}
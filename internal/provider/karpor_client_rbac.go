@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Role is a set of permissions in Karpor.
+type Role struct {
+	Metadata ObjectMeta `json:"metadata"`
+	Spec     RoleSpec   `json:"spec"`
+}
+
+// RoleSpec is the desired state of a role.
+type RoleSpec struct {
+	Description string     `json:"description,omitempty"`
+	Rules       []RoleRule `json:"rules"`
+}
+
+// RoleRule permits verbs on a Karpor resource.
+type RoleRule struct {
+	Resource string   `json:"resource"`
+	Verbs    []string `json:"verbs"`
+}
+
+// RoleBinding grants a role to subjects, optionally scoped to clusters or a
+// resource group rule.
+type RoleBinding struct {
+	Metadata ObjectMeta      `json:"metadata"`
+	Spec     RoleBindingSpec `json:"spec"`
+}
+
+// RoleBindingSpec is the desired state of a role binding.
+type RoleBindingSpec struct {
+	Role              string    `json:"role"`
+	Subjects          []Subject `json:"subjects"`
+	Clusters          []string  `json:"clusters,omitempty"`
+	ResourceGroupRule string    `json:"resourceGroupRule,omitempty"`
+}
+
+// Subject is a user, group or API token a role is granted to.
+type Subject struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Identity is who a Karpor credential authenticates as and what it may do.
+type Identity struct {
+	Name        string       `json:"name"`
+	Kind        string       `json:"kind"`
+	Groups      []string     `json:"groups,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Permission is a verb set an identity is granted on a Karpor resource.
+type Permission struct {
+	Resource string   `json:"resource"`
+	Verbs    []string `json:"verbs"`
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// CreateRole creates a role.
+func (c *KarporClient) CreateRole(ctx context.Context, name string, spec *RoleSpec) (*Role, error) {
+	role := &Role{}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest-api/v1/role/"+url.PathEscape(name), spec, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// GetRole gets a role.
+func (c *KarporClient) GetRole(ctx context.Context, name string) (*Role, error) {
+	role := &Role{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/role/"+url.PathEscape(name), nil, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole replaces the spec of a role.
+func (c *KarporClient) UpdateRole(ctx context.Context, name string, spec *RoleSpec) (*Role, error) {
+	role := &Role{}
+	if err := c.doJSON(ctx, http.MethodPut, "/rest-api/v1/role/"+url.PathEscape(name), spec, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole deletes a role.
+func (c *KarporClient) DeleteRole(ctx context.Context, name string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest-api/v1/role/"+url.PathEscape(name), nil, nil)
+}
+
+// CreateRoleBinding creates a role binding.
+func (c *KarporClient) CreateRoleBinding(ctx context.Context, name string, spec *RoleBindingSpec) (*RoleBinding, error) {
+	binding := &RoleBinding{}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest-api/v1/role-binding/"+url.PathEscape(name), spec, binding); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// GetRoleBinding gets a role binding.
+func (c *KarporClient) GetRoleBinding(ctx context.Context, name string) (*RoleBinding, error) {
+	binding := &RoleBinding{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/role-binding/"+url.PathEscape(name), nil, binding); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// UpdateRoleBinding replaces the spec of a role binding.
+func (c *KarporClient) UpdateRoleBinding(ctx context.Context, name string, spec *RoleBindingSpec) (*RoleBinding, error) {
+	binding := &RoleBinding{}
+	if err := c.doJSON(ctx, http.MethodPut, "/rest-api/v1/role-binding/"+url.PathEscape(name), spec, binding); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// DeleteRoleBinding deletes a role binding.
+func (c *KarporClient) DeleteRoleBinding(ctx context.Context, name string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest-api/v1/role-binding/"+url.PathEscape(name), nil, nil)
+}
+
+// GetCurrentIdentity gets the identity the client authenticates as.
+func (c *KarporClient) GetCurrentIdentity(ctx context.Context) (*Identity, error) {
+	identity := &Identity{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/auth/whoami", nil, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// SyncResourcesRule is a Karpor rule selecting the resources the syncer
+// caches and indexes for a set of clusters.
+type SyncResourcesRule struct {
+	Metadata ObjectMeta            `json:"metadata"`
+	Spec     SyncResourcesRuleSpec `json:"spec"`
+}
+
+// SyncResourcesRuleSpec is the desired state of a sync resources rule.
+type SyncResourcesRuleSpec struct {
+	Clusters             []string          `json:"clusters,omitempty"`
+	ClusterLabelSelector map[string]string `json:"clusterLabelSelector,omitempty"`
+	SyncResources        []SyncResource    `json:"syncResources"`
+}
+
+// SyncResource selects the objects of one resource type to sync.
+type SyncResource struct {
+	APIVersion        string   `json:"apiVersion"`
+	Kind              string   `json:"kind"`
+	Namespaces        []string `json:"namespaces,omitempty"`
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+	LabelSelector     string   `json:"labelSelector,omitempty"`
+	FieldSelector     string   `json:"fieldSelector,omitempty"`
+	MaxAge            string   `json:"maxAge,omitempty"`
+	TransformRefName  string   `json:"transformRefName,omitempty"`
+}
+
+// CreateSyncResourcesRule creates a sync resources rule.
+func (c *KarporClient) CreateSyncResourcesRule(ctx context.Context, name string, spec *SyncResourcesRuleSpec) (*SyncResourcesRule, error) {
+	rule := &SyncResourcesRule{}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest-api/v1/sync-resources-rule/"+url.PathEscape(name), spec, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetSyncResourcesRule gets a sync resources rule.
+func (c *KarporClient) GetSyncResourcesRule(ctx context.Context, name string) (*SyncResourcesRule, error) {
+	rule := &SyncResourcesRule{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/sync-resources-rule/"+url.PathEscape(name), nil, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateSyncResourcesRule replaces the spec of a sync resources rule.
+func (c *KarporClient) UpdateSyncResourcesRule(ctx context.Context, name string, spec *SyncResourcesRuleSpec) (*SyncResourcesRule, error) {
+	rule := &SyncResourcesRule{}
+	if err := c.doJSON(ctx, http.MethodPut, "/rest-api/v1/sync-resources-rule/"+url.PathEscape(name), spec, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteSyncResourcesRule deletes a sync resources rule.
+func (c *KarporClient) DeleteSyncResourcesRule(ctx context.Context, name string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest-api/v1/sync-resources-rule/"+url.PathEscape(name), nil, nil)
+}
+
+// TransformRule is a Karpor rule transforming objects before they are indexed.
+type TransformRule struct {
+	Metadata ObjectMeta        `json:"metadata"`
+	Spec     TransformRuleSpec `json:"spec"`
+}
+
+// TransformRuleSpec is the desired state of a transform rule.
+type TransformRuleSpec struct {
+	Type          string `json:"type"`
+	ValueTemplate string `json:"valueTemplate"`
+}
+
+// CreateTransformRule creates a transform rule.
+func (c *KarporClient) CreateTransformRule(ctx context.Context, name string, spec *TransformRuleSpec) (*TransformRule, error) {
+	rule := &TransformRule{}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest-api/v1/transform-rule/"+url.PathEscape(name), spec, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetTransformRule gets a transform rule.
+func (c *KarporClient) GetTransformRule(ctx context.Context, name string) (*TransformRule, error) {
+	rule := &TransformRule{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/transform-rule/"+url.PathEscape(name), nil, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateTransformRule replaces the spec of a transform rule.
+func (c *KarporClient) UpdateTransformRule(ctx context.Context, name string, spec *TransformRuleSpec) (*TransformRule, error) {
+	rule := &TransformRule{}
+	if err := c.doJSON(ctx, http.MethodPut, "/rest-api/v1/transform-rule/"+url.PathEscape(name), spec, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteTransformRule deletes a transform rule.
+func (c *KarporClient) DeleteTransformRule(ctx context.Context, name string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest-api/v1/transform-rule/"+url.PathEscape(name), nil, nil)
+}
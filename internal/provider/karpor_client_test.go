@@ -0,0 +1,440 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestKarporClientMaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"success": true}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false, WithMaxConcurrentRequests(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.ValidateClusterConfig(context.Background(), "kubeconfig"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestKarporClientRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success": true}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false, WithRateLimit(20, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.ValidateClusterConfig(context.Background(), "kubeconfig"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first request uses the burst, the next four wait 50ms each
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected requests to be rate limited, took %s", elapsed)
+	}
+}
+
+func TestKarporClientLockCluster(t *testing.T) {
+	client, err := NewKarporClient("https://127.0.0.1:7443", "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	unlock, err := client.lockCluster(ctx, "test-cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := client.lockCluster(ctx, "test-cluster")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer unlock()
+		close(locked)
+	}()
+
+	// Other cluster names are not blocked
+	unlockOther, err := client.lockCluster(ctx, "other-cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlockOther()
+
+	// Waiting for the lock gives up when the context is done
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.lockCluster(timeoutCtx, "test-cluster"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the lock to time out, got %v", err)
+	}
+
+	select {
+	case <-locked:
+		t.Fatal("expected the second lock on the same cluster to wait")
+	default:
+	}
+	unlock()
+	<-locked
+}
+
+func TestIsConflict(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&StatusError{StatusCode: http.StatusConflict}, true},
+		{&StatusError{StatusCode: http.StatusNotFound}, false},
+		{errors.New("cluster test-cluster already exists"), true},
+		{errors.New("Operation cannot be fulfilled on clusters \"test-cluster\": the object has been modified"), true},
+		{errors.New("invalid kubeconfig"), false},
+	}
+	for _, tc := range cases {
+		if got := IsConflict(tc.err); got != tc.want {
+			t.Errorf("IsConflict(%v) = %t, want %t", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestMetadataTimestamps(t *testing.T) {
+	metadata := map[string]interface{}{
+		"creationTimestamp": "2024-05-01T08:00:00Z",
+		"managedFields": []interface{}{
+			map[string]interface{}{"manager": "karpor", "time": "2024-05-03T10:30:00Z"},
+			map[string]interface{}{"manager": "terraform", "time": "2024-05-02T09:00:00+08:00"},
+			map[string]interface{}{"manager": "kubectl"},
+		},
+	}
+	createdAt, lastUpdated, err := metadataTimestamps(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := createdAt.Format(time.RFC3339); got != "2024-05-01T08:00:00Z" {
+		t.Errorf("unexpected creation time %s", got)
+	}
+	if got := lastUpdated.Format(time.RFC3339); got != "2024-05-03T10:30:00Z" {
+		t.Errorf("expected the newest managedFields time, got %s", got)
+	}
+
+	// Without managedFields the object was last modified when it was created
+	delete(metadata, "managedFields")
+	_, lastUpdated, err = metadataTimestamps(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lastUpdated.Equal(createdAt) {
+		t.Errorf("expected the creation time, got %s", lastUpdated)
+	}
+
+	metadata["creationTimestamp"] = "Wednesday, 01-May-24 08:00:00 UTC"
+	if _, _, err := metadataTimestamps(metadata); err == nil {
+		t.Error("expected an error for a non-RFC3339 timestamp")
+	}
+}
+
+func TestKarporClientUpdateClusterResourceVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(testClusterObject))
+			return
+		}
+		payload := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		resourceVersion, sent := payload["resourceVersion"]
+		switch {
+		case !sent:
+			_, _ = w.Write([]byte(`{"success": true}`))
+		case resourceVersion == "42":
+			_, _ = w.Write([]byte(`{"success": true}`))
+		default:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"success": false, "message": "the object has been modified"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster := &ClusterRegistrationResourceModel{
+		ClusterName:     types.StringValue("test-cluster"),
+		ResourceVersion: types.StringValue("41"),
+	}
+	if _, err := client.UpdateCluster(context.Background(), cluster, ""); !IsConflict(err) {
+		t.Errorf("expected a conflict for a stale resource version, got %v", err)
+	}
+
+	cluster.ResourceVersion = types.StringValue("42")
+	if _, err := client.UpdateCluster(context.Background(), cluster, ""); err != nil {
+		t.Errorf("expected the current resource version to succeed, got %v", err)
+	}
+
+	// A null resource version forces the update
+	cluster.ResourceVersion = types.StringNull()
+	if _, err := client.UpdateCluster(context.Background(), cluster, ""); err != nil {
+		t.Errorf("expected a forced update to succeed, got %v", err)
+	}
+}
+
+// testClusterObject is a registered cluster carrying Karpor system metadata.
+const testClusterObject = `{"success": true, "data": {
+	"metadata": {
+		"uid": "test-uid",
+		"name": "test-cluster",
+		"labels": {"karpor.io/managed": "true", "env": "dev"},
+		"annotations": {"cluster.karpor.io/synced": "true", "owner": "alice"}
+	},
+	"spec": {"displayName": "", "description": ""}
+}}`
+
+func TestKarporClientUpdateClusterKeepsSystemMetadata(t *testing.T) {
+	var payload struct {
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(testClusterObject))
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write([]byte(`{"success": true}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster := &ClusterRegistrationResourceModel{
+		ClusterName: types.StringValue("test-cluster"),
+		Labels:      types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("prod")}),
+		Annotations: types.MapNull(types.StringType),
+	}
+	if _, err := client.UpdateCluster(context.Background(), cluster, ""); err != nil {
+		t.Fatal(err)
+	}
+	wantLabels := map[string]string{"karpor.io/managed": "true", "env": "prod"}
+	if !reflect.DeepEqual(payload.Labels, wantLabels) {
+		t.Errorf("expected labels %v, got %v", wantLabels, payload.Labels)
+	}
+	wantAnnotations := map[string]string{"cluster.karpor.io/synced": "true"}
+	if !reflect.DeepEqual(payload.Annotations, wantAnnotations) {
+		t.Errorf("expected annotations %v, got %v", wantAnnotations, payload.Annotations)
+	}
+
+	// Unknown annotations are not managed, the remote ones are kept as is
+	cluster.Annotations = types.MapUnknown(types.StringType)
+	if _, err := client.UpdateCluster(context.Background(), cluster, ""); err != nil {
+		t.Fatal(err)
+	}
+	wantAnnotations = map[string]string{"cluster.karpor.io/synced": "true", "owner": "alice"}
+	if !reflect.DeepEqual(payload.Annotations, wantAnnotations) {
+		t.Errorf("expected annotations %v, got %v", wantAnnotations, payload.Annotations)
+	}
+}
+
+func TestKarporClientGetClusterKubeConfigEscapesName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/rest-api/v1/cluster/team%2Fdemo/kubeconfig" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		_, _ = w.Write([]byte(`{"success": true, "data": {"kubeConfig": "apiVersion: v1", "server": "https://karpor", "token": "token"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetClusterKubeConfig(context.Background(), "team/demo", 600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKarporClientSyncResourcesRule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest-api/v1/sync-resources-rule/test-rule" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			spec := SyncResourcesRuleSpec{}
+			if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+				t.Error(err)
+			}
+			if len(spec.SyncResources) != 1 || spec.SyncResources[0].Kind != "Deployment" {
+				t.Errorf("unexpected spec %+v", spec)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": true,
+				"data": SyncResourcesRule{
+					Metadata: ObjectMeta{Name: "test-rule", UID: "uid-1"},
+					Spec:     spec,
+				},
+			})
+		case http.MethodDelete:
+			_, _ = w.Write([]byte(`{"success": false, "message": "rule is in use"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := client.CreateSyncResourcesRule(context.Background(), "test-rule", &SyncResourcesRuleSpec{
+		SyncResources: []SyncResource{{APIVersion: "apps/v1", Kind: "Deployment"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Metadata.UID != "uid-1" || rule.Spec.SyncResources[0].APIVersion != "apps/v1" {
+		t.Errorf("unexpected rule %+v", rule)
+	}
+
+	if err := client.DeleteSyncResourcesRule(context.Background(), "test-rule"); err == nil || err.Error() != "rule is in use" {
+		t.Errorf("expected the Karpor message as error, got %v", err)
+	}
+	if _, err := client.GetSyncResourcesRule(context.Background(), "other-rule"); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestKarporClientUpdateAIBackend(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/rest-api/v1/ai/backend" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		received = map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Error(err)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    map[string]interface{}{"backend": "openai", "model": "gpt-3.5-turbo", "temperature": 1.0, "topP": 1.0},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unset settings and an unchanged token are left to Karpor
+	backend, err := client.UpdateAIBackend(context.Background(), &AIBackend{Backend: "openai"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"authToken", "temperature", "topP", "model"} {
+		if _, ok := received[key]; ok {
+			t.Errorf("expected %s to be omitted, got %v", key, received)
+		}
+	}
+	if backend.Model != "gpt-3.5-turbo" || backend.Temperature == nil || *backend.Temperature != 1 {
+		t.Errorf("unexpected backend %+v", backend)
+	}
+
+	zero := 0.0
+	if _, err := client.UpdateAIBackend(context.Background(), &AIBackend{Backend: "openai", Temperature: &zero, AuthToken: "token"}); err != nil {
+		t.Fatal(err)
+	}
+	if received["authToken"] != "token" || received["temperature"] != 0.0 {
+		t.Errorf("expected the token and a zero temperature to be sent, got %v", received)
+	}
+}
+
+func TestKarporClientProxy(t *testing.T) {
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		if r.URL.Host != "karpor.example.com" {
+			t.Errorf("expected a request for karpor.example.com, got %s", r.URL)
+		}
+		if r.Header.Get("X-Tenant") != "team-a" || r.Header.Get("Authorization") != "Bearer test" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		_, _ = w.Write([]byte(`{"success": true, "data": {"enabled": true}}`))
+	}))
+	defer proxy.Close()
+
+	client, err := NewKarporClient("http://karpor.example.com", "test", false,
+		WithProxy(proxy.URL, ""),
+		WithExtraHeaders(map[string]string{"x-tenant": "team-a"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetAIStatus(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if proxied.Load() != 1 {
+		t.Errorf("expected the request to go through the proxy")
+	}
+
+	// Hosts in no_proxy are reached directly
+	client, err = NewKarporClient("http://karpor.invalid", "test", false, WithProxy(proxy.URL, ".invalid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetAIStatus(context.Background()); err == nil {
+		t.Errorf("expected the direct request to an invalid host to fail")
+	}
+	if proxied.Load() != 1 {
+		t.Errorf("expected the request to bypass the proxy")
+	}
+}
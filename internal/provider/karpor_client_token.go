@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// APIToken is a Karpor access token. Karpor stores its metadata only, the
+// token itself is signed on request for as long as it is not revoked.
+type APIToken struct {
+	Metadata ObjectMeta     `json:"metadata"`
+	Spec     APITokenSpec   `json:"spec"`
+	Status   APITokenStatus `json:"status"`
+}
+
+// APITokenSpec is the desired state of an access token.
+type APITokenSpec struct {
+	Role     string   `json:"role"`
+	Clusters []string `json:"clusters,omitempty"`
+	TTL      string   `json:"ttl,omitempty"`
+}
+
+// APITokenStatus is the observed state of an access token.
+type APITokenStatus struct {
+	CreatedAt string `json:"createdAt,omitempty"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
+// APITokenSecret is a signed access token.
+type APITokenSecret struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
+// CreateAPIToken issues an access token.
+func (c *KarporClient) CreateAPIToken(ctx context.Context, name string, spec *APITokenSpec) (*APIToken, error) {
+	token := &APIToken{}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest-api/v1/api-token/"+url.PathEscape(name), spec, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// GetAPIToken gets the metadata of an access token.
+func (c *KarporClient) GetAPIToken(ctx context.Context, name string) (*APIToken, error) {
+	token := &APIToken{}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest-api/v1/api-token/"+url.PathEscape(name), nil, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// DeleteAPIToken revokes an access token.
+func (c *KarporClient) DeleteAPIToken(ctx context.Context, name string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest-api/v1/api-token/"+url.PathEscape(name), nil, nil)
+}
+
+// GetAPITokenSecret signs an access token.
+func (c *KarporClient) GetAPITokenSecret(ctx context.Context, name string) (*APITokenSecret, error) {
+	secret := &APITokenSecret{}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest-api/v1/api-token/"+url.PathEscape(name)+"/secret", nil, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubeConfig is a kubeconfig file. Fields the provider does not read are
+// kept so that a kubeconfig can be rewritten without losing them.
+type kubeConfig struct {
+	APIVersion     string                   `yaml:"apiVersion,omitempty"`
+	Kind           string                   `yaml:"kind,omitempty"`
+	Preferences    map[string]interface{}   `yaml:"preferences,omitempty"`
+	CurrentContext string                   `yaml:"current-context"`
+	Clusters       []kubeConfigNamedCluster `yaml:"clusters"`
+	Contexts       []kubeConfigNamedContext `yaml:"contexts"`
+	Users          []kubeConfigNamedUser    `yaml:"users"`
+	Extra          map[string]interface{}   `yaml:",inline"`
+}
+
+// kubeConfigNamedCluster is a named cluster entry of a kubeconfig.
+type kubeConfigNamedCluster struct {
+	Name    string `yaml:"name"`
+	Cluster struct {
+		Server string                 `yaml:"server"`
+		Extra  map[string]interface{} `yaml:",inline"`
+	} `yaml:"cluster"`
+}
+
+// kubeConfigNamedContext is a named context entry of a kubeconfig.
+type kubeConfigNamedContext struct {
+	Name    string `yaml:"name"`
+	Context struct {
+		Cluster string                 `yaml:"cluster"`
+		User    string                 `yaml:"user"`
+		Extra   map[string]interface{} `yaml:",inline"`
+	} `yaml:"context"`
+}
+
+// kubeConfigNamedUser is a named user entry of a kubeconfig.
+type kubeConfigNamedUser struct {
+	Name string                 `yaml:"name"`
+	User map[string]interface{} `yaml:"user"`
+}
+
+// parseKubeConfig parses the content of a kubeconfig file.
+func parseKubeConfig(content string) (*kubeConfig, error) {
+	config := &kubeConfig{}
+	if err := yaml.Unmarshal([]byte(content), config); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	return config, nil
+}
+
+// String returns the kubeconfig as YAML.
+func (k *kubeConfig) String() (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(k); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// contextNames returns the names of the contexts in the order they are defined.
+func (k *kubeConfig) contextNames() []string {
+	names := make([]string, 0, len(k.Contexts))
+	for _, context := range k.Contexts {
+		names = append(names, context.Name)
+	}
+	return names
+}
+
+// server returns the API server address of the current context.
+func (k *kubeConfig) server() (string, error) {
+	if k.CurrentContext == "" {
+		return "", fmt.Errorf("kubeconfig has no current-context")
+	}
+	minified, err := k.extract(k.CurrentContext)
+	if err != nil {
+		return "", err
+	}
+	return minified.Clusters[0].Cluster.Server, nil
+}
+
+// minify returns a kubeconfig holding only the current context, its cluster
+// and its user.
+func (k *kubeConfig) minify() (*kubeConfig, error) {
+	if k.CurrentContext == "" {
+		return nil, fmt.Errorf("kubeconfig has no current-context")
+	}
+	return k.extract(k.CurrentContext)
+}
+
+// extract returns a kubeconfig holding only the named context, its cluster
+// and its user, with the context as current-context.
+func (k *kubeConfig) extract(contextName string) (*kubeConfig, error) {
+	var context *kubeConfigNamedContext
+	for i := range k.Contexts {
+		if k.Contexts[i].Name == contextName {
+			context = &k.Contexts[i]
+			break
+		}
+	}
+	if context == nil {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", contextName)
+	}
+
+	extracted := &kubeConfig{
+		APIVersion:     k.APIVersion,
+		Kind:           k.Kind,
+		Preferences:    k.Preferences,
+		CurrentContext: contextName,
+		Contexts:       []kubeConfigNamedContext{*context},
+		Clusters:       []kubeConfigNamedCluster{},
+		Users:          []kubeConfigNamedUser{},
+	}
+	if extracted.APIVersion == "" {
+		extracted.APIVersion = "v1"
+	}
+	if extracted.Kind == "" {
+		extracted.Kind = "Config"
+	}
+
+	for _, cluster := range k.Clusters {
+		if cluster.Name == context.Context.Cluster {
+			extracted.Clusters = append(extracted.Clusters, cluster)
+			break
+		}
+	}
+	if len(extracted.Clusters) == 0 {
+		return nil, fmt.Errorf("cluster %q not found in kubeconfig", context.Context.Cluster)
+	}
+
+	// A context may rely on in-cluster or exec-less anonymous access
+	if context.Context.User != "" {
+		for _, user := range k.Users {
+			if user.Name == context.Context.User {
+				extracted.Users = append(extracted.Users, user)
+				break
+			}
+		}
+		if len(extracted.Users) == 0 {
+			return nil, fmt.Errorf("user %q not found in kubeconfig", context.Context.User)
+		}
+	}
+	return extracted, nil
+}
+
+// sameServer reports whether two API server addresses point at the same
+// server, ignoring case and a trailing slash.
+func sameServer(a string, b string) bool {
+	normalize := func(s string) string {
+		return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "/")
+	}
+	return a != "" && normalize(a) == normalize(b)
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &KubeConfigContextsFunction{}
+
+// NewKubeConfigContextsFunction returns a new function.Function.
+func NewKubeConfigContextsFunction() function.Function {
+	return &KubeConfigContextsFunction{}
+}
+
+// KubeConfigContextsFunction is the kubeconfig_contexts function implementation.
+type KubeConfigContextsFunction struct{}
+
+// Metadata returns the function name.
+func (f *KubeConfigContextsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "kubeconfig_contexts"
+}
+
+// Definition returns the function signature.
+func (f *KubeConfigContextsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "List the contexts of a kubeconfig",
+		Description: "Returns the names of the contexts defined in a kubeconfig, in the order they are defined",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "content",
+				Description: "Kubeconfig content",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+// Run runs the function.
+func (f *KubeConfigContextsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content))
+	if resp.Error != nil {
+		return
+	}
+
+	config, err := parseKubeConfig(content)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, config.contextNames()))
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccKubeConfigContextsFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::kubeconfig_contexts(<<EOT
+` + testKubeConfig + `EOT
+					)
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("dev"),
+						knownvalue.StringExact("prod"),
+					})),
+				},
+			},
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::kubeconfig_contexts("clusters: invalid: yaml")
+				}
+				`,
+				ExpectError: regexp.MustCompile(`invalid kubeconfig`),
+			},
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &KubeConfigExtractFunction{}
+
+// NewKubeConfigExtractFunction returns a new function.Function.
+func NewKubeConfigExtractFunction() function.Function {
+	return &KubeConfigExtractFunction{}
+}
+
+// KubeConfigExtractFunction is the kubeconfig_extract function implementation.
+type KubeConfigExtractFunction struct{}
+
+// Metadata returns the function name.
+func (f *KubeConfigExtractFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "kubeconfig_extract"
+}
+
+// Definition returns the function signature.
+func (f *KubeConfigExtractFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Extract a context from a kubeconfig",
+		Description: "Returns a kubeconfig holding only the given context with its cluster and user, and the context as current-context",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "content",
+				Description: "Kubeconfig content",
+			},
+			function.StringParameter{
+				Name:        "context",
+				Description: "Name of the context to extract",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run runs the function.
+func (f *KubeConfigExtractFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content, contextName string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content, &contextName))
+	if resp.Error != nil {
+		return
+	}
+
+	config, err := parseKubeConfig(content)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	extracted, err := config.extract(contextName)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+	result, err := extracted.String()
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
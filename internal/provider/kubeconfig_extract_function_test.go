@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccKubeConfigExtractFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				locals {
+					kubeconfig = <<EOT
+` + testKubeConfig + `EOT
+				}
+
+				output "test" {
+					value = provider::karpor::kubeconfig_contexts(provider::karpor::kubeconfig_extract(local.kubeconfig, "dev"))
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("dev"),
+					})),
+				},
+			},
+			{
+				Config: providerConfig + `
+				locals {
+					kubeconfig = <<EOT
+` + testKubeConfig + `EOT
+				}
+
+				output "test" {
+					value = provider::karpor::kubeconfig_extract(local.kubeconfig, "staging")
+				}
+				`,
+				ExpectError: regexp.MustCompile(`context "staging" not found`),
+			},
+		},
+	})
+}
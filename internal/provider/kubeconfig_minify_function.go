@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &KubeConfigMinifyFunction{}
+
+// NewKubeConfigMinifyFunction returns a new function.Function.
+func NewKubeConfigMinifyFunction() function.Function {
+	return &KubeConfigMinifyFunction{}
+}
+
+// KubeConfigMinifyFunction is the kubeconfig_minify function implementation.
+type KubeConfigMinifyFunction struct{}
+
+// Metadata returns the function name.
+func (f *KubeConfigMinifyFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "kubeconfig_minify"
+}
+
+// Definition returns the function signature.
+func (f *KubeConfigMinifyFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Minify a kubeconfig",
+		Description: "Returns a kubeconfig holding only the current context with its cluster and user, like `kubectl config view --minify --raw`",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "content",
+				Description: "Kubeconfig content",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run runs the function.
+func (f *KubeConfigMinifyFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content))
+	if resp.Error != nil {
+		return
+	}
+
+	config, err := parseKubeConfig(content)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	minified, err := config.minify()
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	result, err := minified.String()
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
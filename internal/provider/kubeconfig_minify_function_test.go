@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccKubeConfigMinifyFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				locals {
+					minified = provider::karpor::kubeconfig_minify(<<EOT
+` + testKubeConfig + `EOT
+					)
+				}
+
+				output "test" {
+					value = yamldecode(local.minified)["current-context"]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact("prod")),
+				},
+			},
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::kubeconfig_minify("apiVersion: v1")
+				}
+				`,
+				ExpectError: regexp.MustCompile(`kubeconfig has no current-context`),
+			},
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &KubeConfigServerFunction{}
+
+// NewKubeConfigServerFunction returns a new function.Function.
+func NewKubeConfigServerFunction() function.Function {
+	return &KubeConfigServerFunction{}
+}
+
+// KubeConfigServerFunction is the kubeconfig_server function implementation.
+type KubeConfigServerFunction struct{}
+
+// Metadata returns the function name.
+func (f *KubeConfigServerFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "kubeconfig_server"
+}
+
+// Definition returns the function signature.
+func (f *KubeConfigServerFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Get the API server of a kubeconfig",
+		Description: "Returns the API server address of the current context of a kubeconfig, the one `adopt_existing` compares with the registered cluster",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "content",
+				Description: "Kubeconfig content",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run runs the function.
+func (f *KubeConfigServerFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content))
+	if resp.Error != nil {
+		return
+	}
+
+	config, err := parseKubeConfig(content)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	server, err := config.server()
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, server))
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccKubeConfigServerFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::kubeconfig_server(<<EOT
+` + testKubeConfig + `EOT
+					)
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact("https://prod.example.com:6443")),
+				},
+			},
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::kubeconfig_server("apiVersion: v1")
+				}
+				`,
+				ExpectError: regexp.MustCompile(`kubeconfig has no current-context`),
+			},
+		},
+	})
+}
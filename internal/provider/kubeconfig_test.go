@@ -0,0 +1,129 @@
+package provider
+
+import "testing"
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: dev-cluster
+  cluster:
+    certificate-authority-data: ZGV2LWNh
+    server: https://dev.example.com:6443
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: dev-user
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: prod-user
+users:
+- name: dev-user
+  user:
+    token: dev-token
+- name: prod-user
+  user:
+    token: prod-token
+`
+
+func TestKubeConfigServer(t *testing.T) {
+	config, err := parseKubeConfig(testKubeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := config.server()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server != "https://prod.example.com:6443" {
+		t.Errorf("expected the server of the current context, got %s", server)
+	}
+
+	config.CurrentContext = "staging"
+	if _, err := config.server(); err == nil {
+		t.Error("expected an error for a missing context")
+	}
+	config.CurrentContext = ""
+	if _, err := config.server(); err == nil {
+		t.Error("expected an error without current-context")
+	}
+}
+
+func TestSameServer(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://prod.example.com:6443", "https://prod.example.com:6443", true},
+		{"https://prod.example.com:6443/", "https://PROD.example.com:6443", true},
+		{"https://prod.example.com:6443", "https://dev.example.com:6443", false},
+		{"", "", false},
+	}
+	for _, tc := range cases {
+		if got := sameServer(tc.a, tc.b); got != tc.want {
+			t.Errorf("sameServer(%q, %q) = %t, want %t", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestKubeConfigExtract(t *testing.T) {
+	config, err := parseKubeConfig(testKubeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extracted, err := config.extract("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := extracted.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The result is a kubeconfig on its own
+	reparsed, err := parseKubeConfig(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := reparsed.contextNames(); len(names) != 1 || names[0] != "dev" {
+		t.Errorf("expected only the dev context, got %v", names)
+	}
+	if reparsed.CurrentContext != "dev" {
+		t.Errorf("expected dev as current-context, got %s", reparsed.CurrentContext)
+	}
+	if len(reparsed.Users) != 1 || reparsed.Users[0].User["token"] != "dev-token" {
+		t.Errorf("expected only the dev user, got %v", reparsed.Users)
+	}
+	if server, _ := reparsed.server(); server != "https://dev.example.com:6443" {
+		t.Errorf("expected the dev cluster, got %s", server)
+	}
+	if reparsed.Clusters[0].Cluster.Extra["certificate-authority-data"] != "ZGV2LWNh" {
+		t.Error("expected fields not read by the provider to be kept")
+	}
+
+	if _, err := config.extract("staging"); err == nil {
+		t.Error("expected an error for a missing context")
+	}
+}
+
+func TestKubeConfigMinify(t *testing.T) {
+	config, err := parseKubeConfig(testKubeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minified, err := config.minify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := minified.contextNames(); len(names) != 1 || names[0] != "prod" {
+		t.Errorf("expected only the current context, got %v", names)
+	}
+	if len(minified.Clusters) != 1 || minified.Clusters[0].Name != "prod-cluster" {
+		t.Errorf("expected only the prod cluster, got %v", minified.Clusters)
+	}
+}
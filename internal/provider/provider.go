@@ -2,22 +2,39 @@ package provider
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"regexp"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// headerNamePattern matches HTTP header names.
+var headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// reservedHeaders are set by the provider and cannot be set in extra_headers.
+var reservedHeaders = []string{"Authorization", "Content-Type"}
+
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider                   = &KarporProvider{}
-	_ provider.ProviderWithValidateConfig = &KarporProvider{}
+	_ provider.Provider                       = &KarporProvider{}
+	_ provider.ProviderWithValidateConfig     = &KarporProvider{}
+	_ provider.ProviderWithEphemeralResources = &KarporProvider{}
+	_ provider.ProviderWithFunctions          = &KarporProvider{}
 )
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -58,6 +75,75 @@ func (p *KarporProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Optional:    true,
 				Description: "Skip TLS verification, by default it is false",
 			},
+			"proxy_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL of the proxy requests to Karpor are sent through, by default the HTTPS_PROXY and HTTP_PROXY environment variables are used",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(proxyURLPattern, "must be an HTTP, HTTPS or SOCKS5 URL"),
+				},
+			},
+			"no_proxy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comma-separated hosts reached without the proxy, by default the NO_PROXY environment variable is used",
+			},
+			"extra_headers": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "Headers added to every request sent to Karpor, e.g. for an API gateway in front of it",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(headerNamePattern, "must be a valid HTTP header name")),
+				},
+			},
+			"verify_credentials": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Verify the endpoint and API key with one request when the provider is configured, by default it is false",
+			},
+			"otlp_endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: "Base URL of the OTLP/HTTP collector spans of the provider operations and Karpor requests are exported to, e.g. `http://localhost:4318`, by default the OTEL_EXPORTER_OTLP_ENDPOINT environment variable is used and tracing is disabled if neither is set",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpURLPattern, "must be an HTTP or HTTPS URL"),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of requests sent to Karpor at the same time, by default it is unlimited",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"rate_limit": schema.Float64Attribute{
+				Optional:    true,
+				Description: "Maximum number of requests per second sent to Karpor, by default it is unlimited",
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.1),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Default deletion protection of registered clusters, by default it is false",
+			},
+			"rate_limit_burst": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of requests sent at once within `rate_limit`, by default it is 1",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.AlsoRequires(path.MatchRoot("rate_limit")),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"default_labels": schema.SingleNestedBlock{
+				Description: "Labels merged into every label-capable resource, labels set on a resource take precedence",
+				Attributes: map[string]schema.Attribute{
+					"labels": schema.MapAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Default labels",
+					},
+				},
+			},
 		},
 	}
 }
@@ -80,6 +166,7 @@ func (p *KarporProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	if strings.ToLower(os.Getenv("KARPOR_SKIP_TLS_VERIFY")) == "true" {
 		skip_tls_verify = true
 	}
+	verify_credentials := strings.ToLower(os.Getenv("KARPOR_VERIFY_CREDENTIALS")) == "true"
 
 	if !config.ApiEndpoint.IsNull() {
 		api_endpoint = config.ApiEndpoint.ValueString()
@@ -90,6 +177,9 @@ func (p *KarporProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	if !config.SkipTlsVerify.IsNull() {
 		skip_tls_verify = config.SkipTlsVerify.ValueBool()
 	}
+	if !config.VerifyCredentials.IsNull() {
+		verify_credentials = config.VerifyCredentials.ValueBool()
+	}
 
 	if api_endpoint == "" {
 		resp.Diagnostics.AddAttributeError(
@@ -111,14 +201,43 @@ func (p *KarporProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
-	ctx = tflog.SetField(ctx, "endpoint", api_endpoint)
-	ctx = tflog.SetField(ctx, "key", api_key)
+	ctx = tflog.SetField(ctx, "endpoint", redactURL(api_endpoint))
 	ctx = tflog.SetField(ctx, "skip_tls_verify", skip_tls_verify)
-	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "key")
+
+	var opts []KarporClientOption
+	if !config.MaxConcurrentRequests.IsNull() {
+		ctx = tflog.SetField(ctx, "max_concurrent_requests", config.MaxConcurrentRequests.ValueInt64())
+		opts = append(opts, WithMaxConcurrentRequests(int(config.MaxConcurrentRequests.ValueInt64())))
+	}
+	if !config.ProxyURL.IsNull() || !config.NoProxy.IsNull() {
+		opts = append(opts, WithProxy(config.ProxyURL.ValueString(), config.NoProxy.ValueString()))
+	}
+	if !config.ExtraHeaders.IsNull() {
+		opts = append(opts, WithExtraHeaders(stringMapValue(config.ExtraHeaders)))
+	}
+	if !config.RateLimit.IsNull() {
+		burst := int(config.RateLimitBurst.ValueInt64())
+		ctx = tflog.SetField(ctx, "rate_limit", config.RateLimit.ValueFloat64())
+		ctx = tflog.SetField(ctx, "rate_limit_burst", burst)
+		opts = append(opts, WithRateLimit(config.RateLimit.ValueFloat64(), burst))
+	}
+
+	if !config.OtlpEndpoint.IsNull() || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		tflog.Debug(ctx, "Setting up Karpor tracing")
+		if err := setupTracing(ctx, p.version, config.OtlpEndpoint.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("otlp_endpoint"),
+				"Failed to Set Up Karpor Tracing",
+				"An unexpected error occurred when setting up the OpenTelemetry exporter. "+
+					"Unset otlp_endpoint and the OTEL_EXPORTER_OTLP_ENDPOINT environment variable to disable tracing.\n\nError: "+err.Error(),
+			)
+			return
+		}
+	}
 
 	tflog.Debug(ctx, "Creating Karpor client")
 
-	client, err := NewKarporClient(api_endpoint, api_key, skip_tls_verify)
+	client, err := NewKarporClient(api_endpoint, api_key, skip_tls_verify, opts...)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create Karpor client",
 			"An unexpected error occurred when creating the Karpor client. "+
@@ -128,9 +247,23 @@ func (p *KarporProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	client.DeletionProtection = config.DeletionProtection.ValueBool()
+	if config.DefaultLabels != nil {
+		client.DefaultLabels = stringMapValue(config.DefaultLabels.Labels)
+	}
+
+	if verify_credentials {
+		tflog.Debug(ctx, "Verifying Karpor credentials")
+		resp.Diagnostics.Append(verifyCredentials(ctx, client)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Make client available during data source and resource operations
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
 
 	tflog.Debug(ctx, "Karpor client created successfully")
 }
@@ -139,6 +272,14 @@ func (p *KarporProvider) Configure(ctx context.Context, req provider.ConfigureRe
 func (p *KarporProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewClusterRegistrationResource,
+		NewClusterRegistrationsResource,
+		NewSyncResourcesRuleResource,
+		NewTransformRuleResource,
+		NewAIBackendResource,
+		NewAuditPolicyResource,
+		NewAPITokenResource,
+		NewRoleResource,
+		NewRoleBindingResource,
 	}
 }
 
@@ -146,6 +287,28 @@ func (p *KarporProvider) Resources(_ context.Context) []func() resource.Resource
 func (p *KarporProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewClusterDataSource,
+		NewAIStatusDataSource,
+		NewScannerRulesDataSource,
+		NewCurrentIdentityDataSource,
+	}
+}
+
+// EphemeralResources returns the ephemeral resources supported by the provider.
+func (p *KarporProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewClusterKubeConfigEphemeralResource,
+	}
+}
+
+// Functions returns the functions supported by the provider.
+func (p *KarporProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewKubeConfigContextsFunction,
+		NewKubeConfigExtractFunction,
+		NewKubeConfigMinifyFunction,
+		NewKubeConfigServerFunction,
+		NewSearchQueryFunction,
+		NewValidateSearchQueryFunction,
 	}
 }
 
@@ -176,6 +339,27 @@ func (p *KarporProvider) ValidateConfig(ctx context.Context, req provider.Valida
 		)
 	}
 
+	if config.DefaultLabels != nil && config.DefaultLabels.Labels.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_labels").AtName("labels"),
+			"Unknown Karpor Default Labels",
+			"The provider cannot merge default labels into resources as there is an unknown configuration value for the default labels. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	for name := range stringMapValue(config.ExtraHeaders) {
+		for _, reserved := range reservedHeaders {
+			if http.CanonicalHeaderKey(name) == reserved {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("extra_headers"),
+					"Reserved Karpor Header",
+					"The "+reserved+" header is set by the provider and cannot be set in extra_headers.",
+				)
+			}
+		}
+	}
+
 	if config.SkipTlsVerify.ValueBool() {
 		resp.Diagnostics.AddAttributeWarning(
 			path.Root("skip_tls_verify"),
@@ -190,4 +374,24 @@ type KarporProviderModel struct {
 	ApiEndpoint   types.String `tfsdk:"api_endpoint"`
 	ApiKey        types.String `tfsdk:"api_key"`
 	SkipTlsVerify types.Bool   `tfsdk:"skip_tls_verify"`
+
+	VerifyCredentials types.Bool `tfsdk:"verify_credentials"`
+
+	ProxyURL     types.String `tfsdk:"proxy_url"`
+	NoProxy      types.String `tfsdk:"no_proxy"`
+	ExtraHeaders types.Map    `tfsdk:"extra_headers"`
+
+	OtlpEndpoint types.String `tfsdk:"otlp_endpoint"`
+
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RateLimit             types.Float64 `tfsdk:"rate_limit"`
+	RateLimitBurst        types.Int64   `tfsdk:"rate_limit_burst"`
+
+	DeletionProtection types.Bool                        `tfsdk:"deletion_protection"`
+	DefaultLabels      *KarporProviderDefaultLabelsModel `tfsdk:"default_labels"`
+}
+
+// KarporProviderDefaultLabelsModel is the default_labels block model.
+type KarporProviderDefaultLabelsModel struct {
+	Labels types.Map `tfsdk:"labels"`
 }
@@ -17,6 +17,8 @@ provider "karpor" {
 
 var (
 	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-		"karpor": providerserver.NewProtocol6WithError(New("test")()),
+		"karpor": func() (tfprotov6.ProviderServer, error) {
+			return NewTracedProviderServer(providerserver.NewProtocol6(New("test")())()), nil
+		},
 	}
 )
@@ -0,0 +1,297 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// subjectKinds are the kinds of subjects a role is granted to.
+var subjectKinds = []string{"User", "Group", "APIToken"}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &RoleBindingResource{}
+	_ resource.ResourceWithConfigure   = &RoleBindingResource{}
+	_ resource.ResourceWithImportState = &RoleBindingResource{}
+)
+
+// NewRoleBindingResource returns a new resource.Resource.
+func NewRoleBindingResource() resource.Resource {
+	return &RoleBindingResource{}
+}
+
+// RoleBindingResource is the resource implementation.
+type RoleBindingResource struct {
+	client *KarporClient
+}
+
+// RoleBindingResourceModel is the resource model.
+type RoleBindingResourceModel struct {
+	Name              types.String `tfsdk:"name"`
+	Role              types.String `tfsdk:"role"`
+	Subjects          types.List   `tfsdk:"subjects"`
+	Clusters          types.List   `tfsdk:"clusters"`
+	ResourceGroupRule types.String `tfsdk:"resource_group_rule"`
+	Id                types.String `tfsdk:"id"`
+}
+
+// SubjectModel is the model of a subject the role is granted to.
+type SubjectModel struct {
+	Kind types.String `tfsdk:"kind"`
+	Name types.String `tfsdk:"name"`
+}
+
+// subjectAttrTypes are the attribute types of SubjectModel.
+var subjectAttrTypes = map[string]attr.Type{
+	"kind": types.StringType,
+	"name": types.StringType,
+}
+
+// Metadata returns the resource type name.
+func (r *RoleBindingResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_binding"
+}
+
+// Schema returns the resource schema.
+func (r *RoleBindingResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Grant a Karpor role to users, groups and API tokens, optionally scoped to clusters or a resource group rule",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Unique name for the role binding",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the granted `karpor_role`",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"subjects": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "Subjects the role is granted to",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"kind": schema.StringAttribute{
+							Required:    true,
+							Description: "Kind of the subject, one of `User`, `Group` or `APIToken`",
+							Validators: []validator.String{
+								stringvalidator.OneOf(subjectKinds...),
+							},
+						},
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the subject, for `APIToken` the name of the `karpor_api_token`",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+					},
+				},
+			},
+			"clusters": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Names of the clusters the role is granted on, by default it is granted on all clusters",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(path.MatchRoot("resource_group_rule")),
+				},
+			},
+			"resource_group_rule": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the Karpor resource group rule whose resource groups the role is granted on",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource.
+func (r *RoleBindingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RoleBindingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, diags := plan.spec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	binding, err := r.client.CreateRoleBinding(ctx, plan.Name.ValueString(), spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create role binding", err.Error())
+		return
+	}
+	tflog.Info(ctx, "Created role binding", map[string]interface{}{"name": binding.Metadata.Name})
+
+	plan.Id = types.StringValue(binding.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read reads the resource.
+func (r *RoleBindingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RoleBindingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	binding, err := r.client.GetRoleBinding(ctx, state.Name.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "Role binding removed outside of Terraform", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Karpor Role Binding",
+			"Could not read Karpor role binding "+state.Name.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(state.setRemote(ctx, binding)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource.
+func (r *RoleBindingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan RoleBindingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, diags := plan.spec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	binding, err := r.client.UpdateRoleBinding(ctx, plan.Name.ValueString(), spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update role binding", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(binding.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource.
+func (r *RoleBindingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state RoleBindingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteRoleBinding(ctx, state.Name.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Karpor Role Binding",
+			"Could not delete role binding, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource by name.
+func (r *RoleBindingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
+
+// Configure configures the resource.
+func (r *RoleBindingResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// spec converts the model into the role binding spec sent to Karpor.
+func (m *RoleBindingResourceModel) spec(ctx context.Context) (*RoleBindingSpec, diag.Diagnostics) {
+	var subjects []SubjectModel
+	diags := m.Subjects.ElementsAs(ctx, &subjects, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	spec := &RoleBindingSpec{
+		Role:              m.Role.ValueString(),
+		Subjects:          make([]Subject, 0, len(subjects)),
+		Clusters:          stringListValue(m.Clusters),
+		ResourceGroupRule: m.ResourceGroupRule.ValueString(),
+	}
+	for _, subject := range subjects {
+		spec.Subjects = append(spec.Subjects, Subject{
+			Kind: subject.Kind.ValueString(),
+			Name: subject.Name.ValueString(),
+		})
+	}
+	return spec, diags
+}
+
+// setRemote overwrites the model with the role binding read from Karpor.
+func (m *RoleBindingResourceModel) setRemote(ctx context.Context, binding *RoleBinding) diag.Diagnostics {
+	m.Name = types.StringValue(binding.Metadata.Name)
+	m.Id = types.StringValue(binding.Metadata.UID)
+	m.Role = types.StringValue(binding.Spec.Role)
+	m.Clusters = stringListOrNull(binding.Spec.Clusters)
+	m.ResourceGroupRule = stringOrNull(binding.Spec.ResourceGroupRule)
+
+	subjects := make([]SubjectModel, 0, len(binding.Spec.Subjects))
+	for _, subject := range binding.Spec.Subjects {
+		subjects = append(subjects, SubjectModel{
+			Kind: types.StringValue(subject.Kind),
+			Name: types.StringValue(subject.Name),
+		})
+	}
+
+	var diags diag.Diagnostics
+	m.Subjects, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: subjectAttrTypes}, subjects)
+	return diags
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccRoleBinding(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read
+			{
+				Config: providerConfig + `
+				resource "karpor_role" "test" {
+					name  = "test-binding-role"
+					rules = [{ resource = "cluster", verbs = ["get", "list"] }]
+				}
+
+				resource "karpor_role_binding" "test" {
+					name     = "test-role-binding"
+					role     = karpor_role.test.name
+					clusters = ["demo"]
+					subjects = [
+						{ kind = "Group", name = "team-a" },
+					]
+				}
+
+				data "karpor_current_identity" "test" {}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_role_binding.test",
+						tfjsonpath.New("subjects").AtSliceIndex(0).AtMapKey("name"),
+						knownvalue.StringExact("team-a"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.karpor_current_identity.test",
+						tfjsonpath.New("name"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Update
+			{
+				Config: providerConfig + `
+				resource "karpor_role" "test" {
+					name  = "test-binding-role"
+					rules = [{ resource = "cluster", verbs = ["get", "list"] }]
+				}
+
+				resource "karpor_role_binding" "test" {
+					name                = "test-role-binding"
+					role                = karpor_role.test.name
+					resource_group_rule = "namespace"
+					subjects = [
+						{ kind = "Group", name = "team-a" },
+						{ kind = "User", name = "alice" },
+					]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_role_binding.test",
+						tfjsonpath.New("clusters"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_role_binding.test",
+						tfjsonpath.New("subjects").AtSliceIndex(1).AtMapKey("kind"),
+						knownvalue.StringExact("User"),
+					),
+				},
+			},
+			// Import
+			{
+				ResourceName:                         "karpor_role_binding.test",
+				ImportState:                          true,
+				ImportStateId:                        "test-role-binding",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
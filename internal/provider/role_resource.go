@@ -0,0 +1,279 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	// roleResources are the Karpor resources a role grants verbs on.
+	roleResources = []string{"cluster", "search", "insight", "resource_group"}
+	// roleVerbs are the verbs a role may grant, "*" grants all of them.
+	roleVerbs = []string{"get", "list", "create", "update", "delete", "*"}
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &RoleResource{}
+	_ resource.ResourceWithConfigure   = &RoleResource{}
+	_ resource.ResourceWithImportState = &RoleResource{}
+)
+
+// NewRoleResource returns a new resource.Resource.
+func NewRoleResource() resource.Resource {
+	return &RoleResource{}
+}
+
+// RoleResource is the resource implementation.
+type RoleResource struct {
+	client *KarporClient
+}
+
+// RoleResourceModel is the resource model.
+type RoleResourceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Rules       types.List   `tfsdk:"rules"`
+	Id          types.String `tfsdk:"id"`
+}
+
+// RoleRuleModel is the model of the verbs permitted on a Karpor resource.
+type RoleRuleModel struct {
+	Resource types.String `tfsdk:"resource"`
+	Verbs    types.List   `tfsdk:"verbs"`
+}
+
+// roleRuleAttrTypes are the attribute types of RoleRuleModel.
+var roleRuleAttrTypes = map[string]attr.Type{
+	"resource": types.StringType,
+	"verbs":    types.ListType{ElemType: types.StringType},
+}
+
+// Metadata returns the resource type name.
+func (r *RoleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role"
+}
+
+// Schema returns the resource schema.
+func (r *RoleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage a Karpor role, granted to users, groups and API tokens with `karpor_role_binding`",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Unique name for the role",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "Human-readable description",
+			},
+			"rules": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "Verbs permitted on Karpor resources",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"resource": schema.StringAttribute{
+							Required:    true,
+							Description: "Karpor resource, one of `cluster`, `search`, `insight` or `resource_group`",
+							Validators: []validator.String{
+								stringvalidator.OneOf(roleResources...),
+							},
+						},
+						"verbs": schema.ListAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							Description: "Permitted verbs, among `get`, `list`, `create`, `update` and `delete`, or `*` for all of them",
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+								listvalidator.UniqueValues(),
+								listvalidator.ValueStringsAre(stringvalidator.OneOf(roleVerbs...)),
+							},
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource.
+func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, diags := plan.spec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	role, err := r.client.CreateRole(ctx, plan.Name.ValueString(), spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create role", err.Error())
+		return
+	}
+	tflog.Info(ctx, "Created role", map[string]interface{}{"name": role.Metadata.Name})
+
+	plan.Id = types.StringValue(role.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read reads the resource.
+func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	role, err := r.client.GetRole(ctx, state.Name.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "Role removed outside of Terraform", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Karpor Role",
+			"Could not read Karpor role "+state.Name.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(state.setRemote(ctx, role)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource.
+func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan RoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, diags := plan.spec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	role, err := r.client.UpdateRole(ctx, plan.Name.ValueString(), spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update role", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(role.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource.
+func (r *RoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state RoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteRole(ctx, state.Name.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Karpor Role",
+			"Could not delete role, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource by name.
+func (r *RoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
+
+// Configure configures the resource.
+func (r *RoleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// spec converts the model into the role spec sent to Karpor.
+func (m *RoleResourceModel) spec(ctx context.Context) (*RoleSpec, diag.Diagnostics) {
+	var rules []RoleRuleModel
+	diags := m.Rules.ElementsAs(ctx, &rules, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	spec := &RoleSpec{
+		Description: m.Description.ValueString(),
+		Rules:       make([]RoleRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		spec.Rules = append(spec.Rules, RoleRule{
+			Resource: rule.Resource.ValueString(),
+			Verbs:    stringListValue(rule.Verbs),
+		})
+	}
+	return spec, diags
+}
+
+// setRemote overwrites the model with the role read from Karpor.
+func (m *RoleResourceModel) setRemote(ctx context.Context, role *Role) diag.Diagnostics {
+	m.Name = types.StringValue(role.Metadata.Name)
+	m.Id = types.StringValue(role.Metadata.UID)
+	m.Description = stringOrNull(role.Spec.Description)
+
+	rules := make([]RoleRuleModel, 0, len(role.Spec.Rules))
+	for _, rule := range role.Spec.Rules {
+		rules = append(rules, RoleRuleModel{
+			Resource: types.StringValue(rule.Resource),
+			Verbs:    stringListOrNull(rule.Verbs),
+		})
+	}
+
+	var diags diag.Diagnostics
+	m.Rules, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: roleRuleAttrTypes}, rules)
+	return diags
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccRole(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Unknown verbs fail at plan time
+			{
+				Config: providerConfig + `
+				resource "karpor_role" "test" {
+					name  = "test-role"
+					rules = [{ resource = "cluster", verbs = ["watch"] }]
+				}
+				`,
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			// Create and Read
+			{
+				Config: providerConfig + `
+				resource "karpor_role" "test" {
+					name = "test-role"
+					rules = [
+						{ resource = "cluster", verbs = ["get", "list"] },
+						{ resource = "search", verbs = ["get"] },
+					]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_role.test",
+						tfjsonpath.New("rules").AtSliceIndex(0).AtMapKey("verbs"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("get"),
+							knownvalue.StringExact("list"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_role.test",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Update
+			{
+				Config: providerConfig + `
+				resource "karpor_role" "test" {
+					name        = "test-role"
+					description = "Read-only access"
+					rules = [
+						{ resource = "cluster", verbs = ["get", "list"] },
+						{ resource = "search", verbs = ["get"] },
+						{ resource = "insight", verbs = ["*"] },
+					]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_role.test",
+						tfjsonpath.New("rules").AtSliceIndex(2).AtMapKey("resource"),
+						knownvalue.StringExact("insight"),
+					),
+				},
+			},
+			// Import
+			{
+				ResourceName:                         "karpor_role.test",
+				ImportState:                          true,
+				ImportStateId:                        "test-role",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ScannerRulesDataSource{}
+	_ datasource.DataSourceWithConfigure = &ScannerRulesDataSource{}
+)
+
+// NewScannerRulesDataSource returns a new datasource.DataSource.
+func NewScannerRulesDataSource() datasource.DataSource {
+	return &ScannerRulesDataSource{}
+}
+
+// ScannerRulesDataSource is the datasource implementation.
+type ScannerRulesDataSource struct {
+	client *KarporClient
+}
+
+// ScannerRulesDataSourceModel is the datasource model.
+type ScannerRulesDataSourceModel struct {
+	Scanner  types.String            `tfsdk:"scanner"`
+	Severity types.String            `tfsdk:"severity"`
+	Ids      types.List              `tfsdk:"ids"`
+	Rules    []ScannerRuleEntryModel `tfsdk:"rules"`
+}
+
+// ScannerRuleEntryModel is the model of a single scanner rule.
+type ScannerRuleEntryModel struct {
+	Id          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Scanner     types.String `tfsdk:"scanner"`
+	Description types.String `tfsdk:"description"`
+	Severity    types.String `tfsdk:"severity"`
+}
+
+// Metadata returns the metadata for the datasource.
+func (d *ScannerRulesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_scanner_rules"
+}
+
+// Schema returns the schema for the datasource.
+func (d *ScannerRulesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the rules of the Karpor scanner, to reference them in `karpor_audit_policy`",
+		Attributes: map[string]schema.Attribute{
+			"scanner": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the rules of this scanner",
+			},
+			"severity": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the rules of this default severity, one of `Low`, `Medium`, `High` or `Critical`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(auditSeverities...),
+				},
+			},
+			"ids": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "IDs of the listed rules",
+			},
+			"rules": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Listed rules",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the rule",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Human-readable name of the rule",
+						},
+						"scanner": schema.StringAttribute{
+							Computed:    true,
+							Description: "Scanner providing the rule",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "What the rule checks",
+						},
+						"severity": schema.StringAttribute{
+							Computed:    true,
+							Description: "Default severity of the issues reported by the rule",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read reads the datasource.
+func (d *ScannerRulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ScannerRulesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, err := d.client.ListScannerRules(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list scanner rules", err.Error())
+		return
+	}
+
+	ids := []string{}
+	data.Rules = []ScannerRuleEntryModel{}
+	for _, rule := range rules {
+		if !data.Scanner.IsNull() && rule.Scanner != data.Scanner.ValueString() {
+			continue
+		}
+		if !data.Severity.IsNull() && rule.Severity != data.Severity.ValueString() {
+			continue
+		}
+		ids = append(ids, rule.ID)
+		data.Rules = append(data.Rules, ScannerRuleEntryModel{
+			Id:          types.StringValue(rule.ID),
+			Name:        types.StringValue(rule.Name),
+			Scanner:     types.StringValue(rule.Scanner),
+			Description: types.StringValue(rule.Description),
+			Severity:    types.StringValue(rule.Severity),
+		})
+	}
+
+	idList, diags := types.ListValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Ids = idList
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
+// Configure configures the datasource.
+func (d *ScannerRulesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
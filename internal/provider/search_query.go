@@ -0,0 +1,641 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// searchTable is the table Karpor search queries select from.
+const searchTable = "resources"
+
+// searchOperators are the comparison operators supported in search conditions.
+var searchOperators = []string{"=", "!=", "<", "<=", ">", ">=", "like", "not like", "in", "not in"}
+
+// searchFilter is a structured Karpor search filter.
+type searchFilter struct {
+	Clusters   []string
+	Kinds      []string
+	Namespaces []string
+	Labels     map[string]string
+	Conditions []searchCondition
+}
+
+// searchCondition compares a field of the indexed objects with a value.
+type searchCondition struct {
+	Field    string
+	Operator string
+	// Values holds the quoted values, more than one only for in and not in.
+	Values []string
+}
+
+// searchFilterFromValue reads a search filter from an object or map value.
+func searchFilterFromValue(value attr.Value) (*searchFilter, error) {
+	attributes, ok := objectAttributes(value)
+	if !ok {
+		return nil, fmt.Errorf("filter must be an object")
+	}
+
+	filter := &searchFilter{}
+	for name, v := range attributes {
+		if v.IsNull() {
+			continue
+		}
+		var err error
+		switch name {
+		case "clusters":
+			filter.Clusters, err = stringsFromValue(name, v)
+		case "kinds":
+			filter.Kinds, err = stringsFromValue(name, v)
+		case "namespaces":
+			filter.Namespaces, err = stringsFromValue(name, v)
+		case "labels":
+			filter.Labels, err = stringMapFromValue(name, v)
+		case "conditions":
+			filter.Conditions, err = conditionsFromValue(v)
+		default:
+			err = fmt.Errorf("unsupported filter attribute %q, expected one of clusters, kinds, namespaces, labels or conditions", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+// query returns the Karpor SQL query selecting the objects matching the filter.
+func (f *searchFilter) query() string {
+	var predicates []string
+	for _, field := range []struct {
+		name   string
+		values []string
+	}{
+		{"cluster", f.Clusters},
+		{"kind", f.Kinds},
+		{"namespace", f.Namespaces},
+	} {
+		if len(field.values) == 0 {
+			continue
+		}
+		quoted := make([]string, 0, len(field.values))
+		for _, v := range field.values {
+			quoted = append(quoted, quoteSearchString(v))
+		}
+		predicates = append(predicates, searchPredicate(field.name, quoted))
+	}
+
+	// Sort the labels so the query does not change between runs
+	keys := make([]string, 0, len(f.Labels))
+	for k := range f.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		predicates = append(predicates, quoteSearchIdentifier("labels."+k)+" = "+quoteSearchString(f.Labels[k]))
+	}
+
+	for _, c := range f.Conditions {
+		if c.Operator == "in" || c.Operator == "not in" {
+			predicates = append(predicates, quoteSearchIdentifier(c.Field)+" "+c.Operator+" ("+strings.Join(c.Values, ", ")+")")
+			continue
+		}
+		predicates = append(predicates, quoteSearchIdentifier(c.Field)+" "+c.Operator+" "+c.Values[0])
+	}
+
+	query := "select * from " + searchTable
+	if len(predicates) > 0 {
+		query += " where " + strings.Join(predicates, " and ")
+	}
+	return query
+}
+
+// searchPredicate matches a field against one or any of several quoted values.
+func searchPredicate(field string, quoted []string) string {
+	if len(quoted) == 1 {
+		return field + " = " + quoted[0]
+	}
+	return field + " in (" + strings.Join(quoted, ", ") + ")"
+}
+
+// quoteSearchString quotes a string literal, doubling embedded quotes.
+func quoteSearchString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteSearchIdentifier quotes a field name, which may contain characters
+// such as dots, dashes and slashes in label keys.
+func quoteSearchIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// conditionsFromValue reads the conditions of a search filter.
+func conditionsFromValue(value attr.Value) ([]searchCondition, error) {
+	elements, ok := listElements(value)
+	if !ok {
+		return nil, fmt.Errorf("conditions must be a list of objects")
+	}
+
+	conditions := make([]searchCondition, 0, len(elements))
+	for i, element := range elements {
+		attributes, ok := objectAttributes(element)
+		if !ok {
+			return nil, fmt.Errorf("conditions[%d] must be an object", i)
+		}
+		for name := range attributes {
+			if name != "field" && name != "operator" && name != "value" {
+				return nil, fmt.Errorf("unsupported attribute %q in conditions[%d], expected field, operator or value", name, i)
+			}
+		}
+
+		field, ok := stringFromValue(attributes["field"])
+		if !ok || field == "" {
+			return nil, fmt.Errorf("conditions[%d].field must be a non-empty string", i)
+		}
+		operator := "="
+		if v, ok := attributes["operator"]; ok && !v.IsNull() {
+			operator, ok = stringFromValue(v)
+			operator = strings.ToLower(strings.Join(strings.Fields(operator), " "))
+			if !ok || !slices.Contains(searchOperators, operator) {
+				return nil, fmt.Errorf("conditions[%d].operator must be one of %s", i, strings.Join(searchOperators, ", "))
+			}
+		}
+
+		v, ok := attributes["value"]
+		if !ok || v.IsNull() {
+			return nil, fmt.Errorf("conditions[%d].value is required", i)
+		}
+		var values []string
+		switch operator {
+		case "in", "not in":
+			items, ok := listElements(v)
+			if !ok || len(items) == 0 {
+				return nil, fmt.Errorf("conditions[%d].value must be a non-empty list for operator %q", i, operator)
+			}
+			for _, item := range items {
+				literal, err := searchLiteral(item)
+				if err != nil {
+					return nil, fmt.Errorf("conditions[%d].value: %w", i, err)
+				}
+				values = append(values, literal)
+			}
+		case "like", "not like":
+			// Karpor only matches patterns against strings
+			pattern, ok := stringFromValue(v)
+			if !ok {
+				return nil, fmt.Errorf("conditions[%d].value must be a string for operator %q", i, operator)
+			}
+			values = []string{quoteSearchString(pattern)}
+		default:
+			literal, err := searchLiteral(v)
+			if err != nil {
+				return nil, fmt.Errorf("conditions[%d].value: %w", i, err)
+			}
+			values = []string{literal}
+		}
+
+		conditions = append(conditions, searchCondition{
+			Field:    field,
+			Operator: operator,
+			Values:   values,
+		})
+	}
+	return conditions, nil
+}
+
+// searchLiteral returns a string, number or bool value as a query literal.
+func searchLiteral(value attr.Value) (string, error) {
+	switch v := value.(type) {
+	case types.String:
+		return quoteSearchString(v.ValueString()), nil
+	case types.Number:
+		return v.ValueBigFloat().Text('f', -1), nil
+	case types.Bool:
+		return fmt.Sprintf("%t", v.ValueBool()), nil
+	default:
+		return "", fmt.Errorf("expected a string, number or bool")
+	}
+}
+
+// stringsFromValue reads a list of strings, a single string is a list of one.
+func stringsFromValue(name string, value attr.Value) ([]string, error) {
+	if s, ok := stringFromValue(value); ok {
+		return []string{s}, nil
+	}
+	elements, ok := listElements(value)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of strings", name)
+	}
+	result := make([]string, 0, len(elements))
+	for _, element := range elements {
+		s, ok := stringFromValue(element)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a list of strings", name)
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// stringMapFromValue reads a map of strings from an object or map value.
+func stringMapFromValue(name string, value attr.Value) (map[string]string, error) {
+	attributes, ok := objectAttributes(value)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map of strings", name)
+	}
+	result := make(map[string]string, len(attributes))
+	for k, v := range attributes {
+		s, ok := stringFromValue(v)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a map of strings", name)
+		}
+		result[k] = s
+	}
+	return result, nil
+}
+
+// objectAttributes returns the attributes of an object or the elements of a map.
+func objectAttributes(value attr.Value) (map[string]attr.Value, bool) {
+	switch v := value.(type) {
+	case types.Dynamic:
+		return objectAttributes(v.UnderlyingValue())
+	case types.Object:
+		return v.Attributes(), true
+	case types.Map:
+		return v.Elements(), true
+	default:
+		return nil, false
+	}
+}
+
+// listElements returns the elements of a tuple, list or set.
+func listElements(value attr.Value) ([]attr.Value, bool) {
+	switch v := value.(type) {
+	case types.Dynamic:
+		return listElements(v.UnderlyingValue())
+	case types.Tuple:
+		return v.Elements(), true
+	case types.List:
+		return v.Elements(), true
+	case types.Set:
+		return v.Elements(), true
+	default:
+		return nil, false
+	}
+}
+
+// stringFromValue returns the value of a known string.
+func stringFromValue(value attr.Value) (string, bool) {
+	switch v := value.(type) {
+	case types.Dynamic:
+		return stringFromValue(v.UnderlyingValue())
+	case types.String:
+		return v.ValueString(), !v.IsNull() && !v.IsUnknown()
+	default:
+		return "", false
+	}
+}
+
+// searchToken is a lexical token of a search query.
+type searchToken struct {
+	kind  searchTokenKind
+	text  string
+	value string
+	pos   int
+}
+
+// searchTokenKind is the kind of a search token.
+type searchTokenKind int
+
+const (
+	searchTokenEOF searchTokenKind = iota
+	searchTokenIdentifier
+	searchTokenKeyword
+	searchTokenString
+	searchTokenNumber
+	searchTokenSymbol
+)
+
+// searchKeywords are the reserved words of the search query language.
+var searchKeywords = map[string]bool{
+	"select": true, "from": true, "where": true, "and": true, "or": true, "not": true,
+	"in": true, "like": true, "is": true, "null": true, "true": true, "false": true,
+	"order": true, "by": true, "asc": true, "desc": true, "limit": true,
+}
+
+// String describes the token in error messages.
+func (t searchToken) String() string {
+	if t.kind == searchTokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// tokenizeSearchQuery splits a search query into tokens.
+func tokenizeSearchQuery(query string) ([]searchToken, error) {
+	var tokens []searchToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '\'' || r == '`':
+			var value strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("syntax error at position %d: unterminated %c", start+1, r)
+				}
+				if runes[i] == r {
+					// A doubled quote is an escaped quote
+					if i+1 < len(runes) && runes[i+1] == r {
+						value.WriteRune(r)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			kind := searchTokenString
+			if r == '`' {
+				kind = searchTokenIdentifier
+			}
+			tokens = append(tokens, searchToken{kind: kind, text: string(runes[start:i]), value: value.String(), pos: start})
+			continue
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			tokens = append(tokens, searchToken{kind: searchTokenNumber, text: text, value: text, pos: start})
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			kind := searchTokenIdentifier
+			if searchKeywords[strings.ToLower(text)] {
+				kind = searchTokenKeyword
+			}
+			tokens = append(tokens, searchToken{kind: kind, text: text, value: strings.ToLower(text), pos: start})
+			continue
+		}
+
+		// Symbols, longest first
+		matched := false
+		for _, symbol := range []string{"<=", ">=", "!=", "<>", "=", "<", ">", "(", ")", ",", "*"} {
+			if strings.HasPrefix(string(runes[i:]), symbol) {
+				tokens = append(tokens, searchToken{kind: searchTokenSymbol, text: symbol, value: symbol, pos: start})
+				i += len([]rune(symbol))
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("syntax error at position %d: unexpected character %q", start+1, r)
+		}
+	}
+	return append(tokens, searchToken{kind: searchTokenEOF, pos: len(runes)}), nil
+}
+
+// searchQueryParser checks a search query against the grammar Karpor accepts:
+//
+//	query     = "select" columns "from" table [ "where" expr ] [ "order" "by" order ] [ "limit" number ]
+//	expr      = term { "or" term }
+//	term      = factor { "and" factor }
+//	factor    = "not" factor | "(" expr ")" | predicate
+//	predicate = field ( compare value | [ "not" ] "in" "(" values ")" | [ "not" ] "like" string | "is" [ "not" ] "null" )
+type searchQueryParser struct {
+	tokens []searchToken
+	pos    int
+}
+
+// validateSearchQuery returns a syntax error describing the first problem in query.
+func validateSearchQuery(query string) error {
+	tokens, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return err
+	}
+	p := &searchQueryParser{tokens: tokens}
+	return p.parseQuery()
+}
+
+func (p *searchQueryParser) peek() searchToken {
+	return p.tokens[p.pos]
+}
+
+func (p *searchQueryParser) next() searchToken {
+	t := p.tokens[p.pos]
+	if t.kind != searchTokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the given keyword or symbol.
+func (p *searchQueryParser) accept(value string) bool {
+	t := p.peek()
+	if (t.kind == searchTokenKeyword || t.kind == searchTokenSymbol) && t.value == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *searchQueryParser) expect(value string) error {
+	if !p.accept(value) {
+		return p.errorf("expected %q", value)
+	}
+	return nil
+}
+
+func (p *searchQueryParser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	return fmt.Errorf("syntax error at position %d: %s, got %s", t.pos+1, fmt.Sprintf(format, args...), t)
+}
+
+func (p *searchQueryParser) parseQuery() error {
+	if err := p.expect("select"); err != nil {
+		return err
+	}
+	if !p.accept("*") {
+		if err := p.parseFieldList(); err != nil {
+			return err
+		}
+	}
+	if err := p.expect("from"); err != nil {
+		return err
+	}
+	table := p.peek()
+	if table.kind != searchTokenIdentifier {
+		return p.errorf("expected a table name")
+	}
+	if !strings.EqualFold(table.value, searchTable) {
+		return fmt.Errorf("syntax error at position %d: unknown table %s, Karpor searches the %s table", table.pos+1, table, searchTable)
+	}
+	p.next()
+
+	if p.accept("where") {
+		if err := p.parseExpr(); err != nil {
+			return err
+		}
+	}
+	if p.accept("order") {
+		if err := p.expect("by"); err != nil {
+			return err
+		}
+		for {
+			if err := p.parseField(); err != nil {
+				return err
+			}
+			if !p.accept("asc") {
+				p.accept("desc")
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("limit") {
+		if p.peek().kind != searchTokenNumber {
+			return p.errorf("expected a number")
+		}
+		p.next()
+	}
+	if p.peek().kind != searchTokenEOF {
+		return p.errorf("expected end of query")
+	}
+	return nil
+}
+
+func (p *searchQueryParser) parseFieldList() error {
+	for {
+		if err := p.parseField(); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+func (p *searchQueryParser) parseField() error {
+	if p.peek().kind != searchTokenIdentifier {
+		return p.errorf("expected a field name")
+	}
+	p.next()
+	return nil
+}
+
+func (p *searchQueryParser) parseExpr() error {
+	for {
+		if err := p.parseTerm(); err != nil {
+			return err
+		}
+		if !p.accept("or") {
+			return nil
+		}
+	}
+}
+
+func (p *searchQueryParser) parseTerm() error {
+	for {
+		if err := p.parseFactor(); err != nil {
+			return err
+		}
+		if !p.accept("and") {
+			return nil
+		}
+	}
+}
+
+func (p *searchQueryParser) parseFactor() error {
+	if p.accept("not") {
+		return p.parseFactor()
+	}
+	if p.accept("(") {
+		if err := p.parseExpr(); err != nil {
+			return err
+		}
+		return p.expect(")")
+	}
+	return p.parsePredicate()
+}
+
+func (p *searchQueryParser) parsePredicate() error {
+	if err := p.parseField(); err != nil {
+		return err
+	}
+
+	switch {
+	case p.accept("is"):
+		p.accept("not")
+		return p.expect("null")
+	case p.accept("not"):
+		if p.accept("like") {
+			return p.parseString()
+		}
+		if err := p.expect("in"); err != nil {
+			return err
+		}
+		return p.parseValueList()
+	case p.accept("like"):
+		return p.parseString()
+	case p.accept("in"):
+		return p.parseValueList()
+	}
+
+	for _, operator := range []string{"=", "!=", "<>", "<=", ">=", "<", ">"} {
+		if p.accept(operator) {
+			return p.parseValue()
+		}
+	}
+	return p.errorf("expected a comparison operator")
+}
+
+func (p *searchQueryParser) parseValueList() error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	return p.expect(")")
+}
+
+func (p *searchQueryParser) parseValue() error {
+	t := p.peek()
+	switch {
+	case t.kind == searchTokenString, t.kind == searchTokenNumber:
+	case t.kind == searchTokenKeyword && (t.value == "true" || t.value == "false"):
+	default:
+		return p.errorf("expected a string, number or bool")
+	}
+	p.next()
+	return nil
+}
+
+func (p *searchQueryParser) parseString() error {
+	if p.peek().kind != searchTokenString {
+		return p.errorf("expected a string")
+	}
+	p.next()
+	return nil
+}
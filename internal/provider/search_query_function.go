@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &SearchQueryFunction{}
+
+// NewSearchQueryFunction returns a new function.Function.
+func NewSearchQueryFunction() function.Function {
+	return &SearchQueryFunction{}
+}
+
+// SearchQueryFunction is the search_query function implementation.
+type SearchQueryFunction struct{}
+
+// Metadata returns the function name.
+func (f *SearchQueryFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "search_query"
+}
+
+// Definition returns the function signature.
+func (f *SearchQueryFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build a Karpor search query",
+		Description: "Returns a Karpor SQL query selecting the resources matching a filter object. All filter attributes are optional and combined with `and`: " +
+			"`clusters`, `kinds` and `namespaces` (string or list of strings, any of which matches), `labels` (map of label values) and " +
+			"`conditions` (list of objects with `field`, `operator` and `value`, the operator is one of `=`, `!=`, `<`, `<=`, `>`, `>=`, `like`, `not like`, `in` and `not in` and defaults to `=`, `like` and `not like` take a string pattern)",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "filter",
+				Description: "Filter object",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run runs the function.
+func (f *SearchQueryFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var filterValue types.Dynamic
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &filterValue))
+	if resp.Error != nil {
+		return
+	}
+
+	filter, err := searchFilterFromValue(filterValue)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, filter.query()))
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccSearchQueryFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::search_query({
+						clusters   = ["prod"]
+						kinds      = ["Pod", "Deployment"]
+						namespaces = "default"
+						labels = {
+							app = "web"
+						}
+						conditions = [
+							{ field = "spec.replicas", operator = ">=", value = 2 },
+						]
+					})
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact(
+						"select * from resources where cluster = 'prod' and kind in ('Pod', 'Deployment') and namespace = 'default'"+
+							" and `labels.app` = 'web' and `spec.replicas` >= 2",
+					)),
+				},
+			},
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::search_query({ kind = "Pod" })
+				}
+				`,
+				ExpectError: regexp.MustCompile(`unsupported filter attribute "kind"`),
+			},
+		},
+	})
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSearchFilterQuery(t *testing.T) {
+	conditionType := map[string]attr.Type{
+		"field":    types.StringType,
+		"operator": types.StringType,
+		"value":    types.NumberType,
+	}
+	filter := types.ObjectValueMust(
+		map[string]attr.Type{
+			"clusters": types.TupleType{ElemTypes: []attr.Type{types.StringType, types.StringType}},
+			"kinds":    types.StringType,
+			"labels":   types.ObjectType{AttrTypes: map[string]attr.Type{"app.kubernetes.io/name": types.StringType, "team": types.StringType}},
+			"conditions": types.TupleType{ElemTypes: []attr.Type{
+				types.ObjectType{AttrTypes: map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.StringType}},
+				types.ObjectType{AttrTypes: conditionType},
+			}},
+		},
+		map[string]attr.Value{
+			"clusters": types.TupleValueMust(
+				[]attr.Type{types.StringType, types.StringType},
+				[]attr.Value{types.StringValue("prod"), types.StringValue("o'brien")},
+			),
+			"kinds": types.StringValue("Deployment"),
+			"labels": types.ObjectValueMust(
+				map[string]attr.Type{"app.kubernetes.io/name": types.StringType, "team": types.StringType},
+				map[string]attr.Value{"app.kubernetes.io/name": types.StringValue("web"), "team": types.StringValue("search")},
+			),
+			"conditions": types.TupleValueMust(
+				[]attr.Type{
+					types.ObjectType{AttrTypes: map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.StringType}},
+					types.ObjectType{AttrTypes: conditionType},
+				},
+				[]attr.Value{
+					types.ObjectValueMust(
+						map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.StringType},
+						map[string]attr.Value{"field": types.StringValue("name"), "operator": types.StringValue("LIKE"), "value": types.StringValue("web-%")},
+					),
+					types.ObjectValueMust(conditionType, map[string]attr.Value{
+						"field":    types.StringValue("spec.replicas"),
+						"operator": types.StringValue(">"),
+						"value":    types.NumberValue(big.NewFloat(2)),
+					}),
+				},
+			),
+		},
+	)
+
+	parsed, err := searchFilterFromValue(types.DynamicValue(filter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.query()
+	expected := "select * from resources where cluster in ('prod', 'o''brien') and kind = 'Deployment'" +
+		" and `labels.app.kubernetes.io/name` = 'web' and `labels.team` = 'search'" +
+		" and `name` like 'web-%' and `spec.replicas` > 2"
+	if query != expected {
+		t.Errorf("unexpected query\n got: %s\nwant: %s", query, expected)
+	}
+	if err := validateSearchQuery(query); err != nil {
+		t.Errorf("expected the built query to be valid, got %s", err)
+	}
+}
+
+func TestSearchFilterInvalid(t *testing.T) {
+	cases := map[string]attr.Value{
+		"filter must be an object": types.StringValue("kind = 'Pod'"),
+		"unsupported filter attribute \"kind\"": types.ObjectValueMust(
+			map[string]attr.Type{"kind": types.StringType},
+			map[string]attr.Value{"kind": types.StringValue("Pod")},
+		),
+		"conditions[0].operator must be one of": types.ObjectValueMust(
+			map[string]attr.Type{"conditions": types.TupleType{ElemTypes: []attr.Type{
+				types.ObjectType{AttrTypes: map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.StringType}},
+			}}},
+			map[string]attr.Value{"conditions": types.TupleValueMust(
+				[]attr.Type{types.ObjectType{AttrTypes: map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.StringType}}},
+				[]attr.Value{types.ObjectValueMust(
+					map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.StringType},
+					map[string]attr.Value{"field": types.StringValue("name"), "operator": types.StringValue("=="), "value": types.StringValue("web")},
+				)},
+			)},
+		),
+		"conditions[0].value must be a string for operator \"not like\"": types.ObjectValueMust(
+			map[string]attr.Type{"conditions": types.TupleType{ElemTypes: []attr.Type{
+				types.ObjectType{AttrTypes: map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.NumberType}},
+			}}},
+			map[string]attr.Value{"conditions": types.TupleValueMust(
+				[]attr.Type{types.ObjectType{AttrTypes: map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.NumberType}}},
+				[]attr.Value{types.ObjectValueMust(
+					map[string]attr.Type{"field": types.StringType, "operator": types.StringType, "value": types.NumberType},
+					map[string]attr.Value{"field": types.StringValue("name"), "operator": types.StringValue("not like"), "value": types.NumberValue(big.NewFloat(42))},
+				)},
+			)},
+		),
+	}
+	for want, value := range cases {
+		_, err := searchFilterFromValue(value)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestValidateSearchQuery(t *testing.T) {
+	valid := []string{
+		"select * from resources",
+		"SELECT * FROM resources WHERE kind = 'Pod' AND namespace = 'default'",
+		"select name, namespace from resources where (kind = 'Pod' or kind = 'Deployment') and not cluster in ('a', 'b')",
+		"select * from resources where `labels.app` != 'it''s' and name not like 'tmp-%' order by name desc limit 10",
+		"select * from resources where deletionTimestamp is not null",
+	}
+	for _, query := range valid {
+		if err := validateSearchQuery(query); err != nil {
+			t.Errorf("expected %q to be valid, got %s", query, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"select * from resources where kind = 'Pod":        "position 38: unterminated '",
+		"select * from resources where kind = Pod":         "position 38: expected a string, number or bool, got \"Pod\"",
+		"select * from resources where kind 'Pod'":         "position 36: expected a comparison operator",
+		"select * from pods":                               "unknown table \"pods\"",
+		"select * from resources where":                    "expected a field name, got end of query",
+		"select * from resources where (kind = 'Pod'":      "expected \")\", got end of query",
+		"select * from resources where kind = 'Pod' limit": "expected a number, got end of query",
+		"select * resources":                               "expected \"from\"",
+		"select * from resources where kind = 'Pod';":      "unexpected character ';'",
+	}
+	for query, want := range invalid {
+		err := validateSearchQuery(query)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q to fail with %q, got %v", query, want, err)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// durationPattern matches Go durations such as "72h" or "1h30m".
+var durationPattern = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &SyncResourcesRuleResource{}
+	_ resource.ResourceWithConfigure   = &SyncResourcesRuleResource{}
+	_ resource.ResourceWithImportState = &SyncResourcesRuleResource{}
+)
+
+// NewSyncResourcesRuleResource returns a new resource.Resource.
+func NewSyncResourcesRuleResource() resource.Resource {
+	return &SyncResourcesRuleResource{}
+}
+
+// SyncResourcesRuleResource is the resource implementation.
+type SyncResourcesRuleResource struct {
+	client *KarporClient
+}
+
+// SyncResourcesRuleResourceModel is the resource model.
+type SyncResourcesRuleResourceModel struct {
+	Name          types.String `tfsdk:"name"`
+	Clusters      types.List   `tfsdk:"clusters"`
+	ClusterLabels types.Map    `tfsdk:"cluster_labels"`
+	Resources     types.List   `tfsdk:"resources"`
+	Id            types.String `tfsdk:"id"`
+}
+
+// SyncResourcesRuleEntryModel is the model of a single synced resource type.
+type SyncResourcesRuleEntryModel struct {
+	ApiVersion        types.String `tfsdk:"api_version"`
+	Kind              types.String `tfsdk:"kind"`
+	Namespaces        types.List   `tfsdk:"namespaces"`
+	ExcludeNamespaces types.List   `tfsdk:"exclude_namespaces"`
+	LabelSelector     types.String `tfsdk:"label_selector"`
+	FieldSelector     types.String `tfsdk:"field_selector"`
+	MaxAge            types.String `tfsdk:"max_age"`
+	TransformRule     types.String `tfsdk:"transform_rule"`
+}
+
+// syncResourcesRuleEntryAttrTypes are the attribute types of SyncResourcesRuleEntryModel.
+var syncResourcesRuleEntryAttrTypes = map[string]attr.Type{
+	"api_version":        types.StringType,
+	"kind":               types.StringType,
+	"namespaces":         types.ListType{ElemType: types.StringType},
+	"exclude_namespaces": types.ListType{ElemType: types.StringType},
+	"label_selector":     types.StringType,
+	"field_selector":     types.StringType,
+	"max_age":            types.StringType,
+	"transform_rule":     types.StringType,
+}
+
+// Metadata returns the resource type name.
+func (r *SyncResourcesRuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sync_resources_rule"
+}
+
+// Schema returns the resource schema.
+func (r *SyncResourcesRuleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage the resources Karpor syncs and indexes for a set of clusters",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Unique name for the rule",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"clusters": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Names of the clusters the rule applies to, by default it applies to all clusters",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(path.MatchRoot("cluster_labels")),
+				},
+			},
+			"cluster_labels": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Labels selecting the clusters the rule applies to",
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+				},
+			},
+			"resources": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "Resource types to sync",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"api_version": schema.StringAttribute{
+							Required:    true,
+							Description: "API version of the resource, e.g. `apps/v1`",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"kind": schema.StringAttribute{
+							Required:    true,
+							Description: "Kind of the resource, e.g. `Deployment`",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"namespaces": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Namespaces to sync, by default all namespaces are synced",
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+								listvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("exclude_namespaces")),
+							},
+						},
+						"exclude_namespaces": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Namespaces not to sync",
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+							},
+						},
+						"label_selector": schema.StringAttribute{
+							Optional:    true,
+							Description: "Kubernetes label selector of the objects to sync, e.g. `app=web,tier!=cache`",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"field_selector": schema.StringAttribute{
+							Optional:    true,
+							Description: "Kubernetes field selector of the objects to sync, e.g. `status.phase=Running`",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"max_age": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only sync objects changed within this duration, e.g. `72h`",
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(durationPattern, "must be a duration such as \"72h\" or \"1h30m\""),
+							},
+						},
+						"transform_rule": schema.StringAttribute{
+							Optional:    true,
+							Description: "Name of the `karpor_transform_rule` applied to the objects before they are indexed",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource.
+func (r *SyncResourcesRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SyncResourcesRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, diags := plan.spec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, err := r.client.CreateSyncResourcesRule(ctx, plan.Name.ValueString(), spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create sync resources rule", err.Error())
+		return
+	}
+	tflog.Info(ctx, "Created sync resources rule", map[string]interface{}{"name": rule.Metadata.Name})
+
+	plan.Id = types.StringValue(rule.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read reads the resource.
+func (r *SyncResourcesRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SyncResourcesRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, err := r.client.GetSyncResourcesRule(ctx, state.Name.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "Sync resources rule removed outside of Terraform", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Karpor Sync Resources Rule",
+			"Could not read Karpor sync resources rule "+state.Name.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(state.setRemote(ctx, rule)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource.
+func (r *SyncResourcesRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan SyncResourcesRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, diags := plan.spec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, err := r.client.UpdateSyncResourcesRule(ctx, plan.Name.ValueString(), spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update sync resources rule", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(rule.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource.
+func (r *SyncResourcesRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SyncResourcesRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteSyncResourcesRule(ctx, state.Name.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Karpor Sync Resources Rule",
+			"Could not delete sync resources rule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource by name.
+func (r *SyncResourcesRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
+
+// Configure configures the resource.
+func (r *SyncResourcesRuleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// spec converts the model into the rule spec sent to Karpor.
+func (m *SyncResourcesRuleResourceModel) spec(ctx context.Context) (*SyncResourcesRuleSpec, diag.Diagnostics) {
+	var entries []SyncResourcesRuleEntryModel
+	diags := m.Resources.ElementsAs(ctx, &entries, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	spec := &SyncResourcesRuleSpec{
+		Clusters:             stringListValue(m.Clusters),
+		ClusterLabelSelector: stringMapValue(m.ClusterLabels),
+		SyncResources:        make([]SyncResource, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		spec.SyncResources = append(spec.SyncResources, SyncResource{
+			APIVersion:        entry.ApiVersion.ValueString(),
+			Kind:              entry.Kind.ValueString(),
+			Namespaces:        stringListValue(entry.Namespaces),
+			ExcludeNamespaces: stringListValue(entry.ExcludeNamespaces),
+			LabelSelector:     entry.LabelSelector.ValueString(),
+			FieldSelector:     entry.FieldSelector.ValueString(),
+			MaxAge:            entry.MaxAge.ValueString(),
+			TransformRefName:  entry.TransformRule.ValueString(),
+		})
+	}
+	return spec, diags
+}
+
+// setRemote overwrites the model with the rule read from Karpor.
+func (m *SyncResourcesRuleResourceModel) setRemote(ctx context.Context, rule *SyncResourcesRule) diag.Diagnostics {
+	m.Name = types.StringValue(rule.Metadata.Name)
+	m.Id = types.StringValue(rule.Metadata.UID)
+	m.Clusters = stringListOrNull(rule.Spec.Clusters)
+	m.ClusterLabels = stringMapOrNull(rule.Spec.ClusterLabelSelector)
+
+	entries := make([]SyncResourcesRuleEntryModel, 0, len(rule.Spec.SyncResources))
+	for _, syncResource := range rule.Spec.SyncResources {
+		entries = append(entries, SyncResourcesRuleEntryModel{
+			ApiVersion:        types.StringValue(syncResource.APIVersion),
+			Kind:              types.StringValue(syncResource.Kind),
+			Namespaces:        stringListOrNull(syncResource.Namespaces),
+			ExcludeNamespaces: stringListOrNull(syncResource.ExcludeNamespaces),
+			LabelSelector:     stringOrNull(syncResource.LabelSelector),
+			FieldSelector:     stringOrNull(syncResource.FieldSelector),
+			MaxAge:            stringOrNull(syncResource.MaxAge),
+			TransformRule:     stringOrNull(syncResource.TransformRefName),
+		})
+	}
+
+	var diags diag.Diagnostics
+	m.Resources, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: syncResourcesRuleEntryAttrTypes}, entries)
+	return diags
+}
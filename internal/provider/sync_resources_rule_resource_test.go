@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccSyncResourcesRule(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read
+			{
+				Config: providerConfig + `
+				resource "karpor_sync_resources_rule" "test" {
+					name     = "test-sync-rule"
+					clusters = ["demo"]
+					resources = [
+						{
+							api_version = "apps/v1"
+							kind        = "Deployment"
+							namespaces  = ["default"]
+						},
+					]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_sync_resources_rule.test",
+						tfjsonpath.New("resources").AtSliceIndex(0).AtMapKey("kind"),
+						knownvalue.StringExact("Deployment"),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_sync_resources_rule.test",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			// Update
+			{
+				Config: providerConfig + `
+				resource "karpor_sync_resources_rule" "test" {
+					name     = "test-sync-rule"
+					clusters = ["demo"]
+					resources = [
+						{
+							api_version        = "apps/v1"
+							kind               = "Deployment"
+							exclude_namespaces = ["kube-system"]
+							label_selector     = "app=web"
+						},
+						{
+							api_version = "v1"
+							kind        = "Event"
+							max_age     = "72h"
+						},
+					]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_sync_resources_rule.test",
+						tfjsonpath.New("resources").AtSliceIndex(1).AtMapKey("max_age"),
+						knownvalue.StringExact("72h"),
+					),
+				},
+			},
+			// Import
+			{
+				ResourceName:                         "karpor_sync_resources_rule.test",
+				ImportState:                          true,
+				ImportStateId:                        "test-sync-rule",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
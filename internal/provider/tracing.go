@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the provider spans.
+const tracerName = "github.com/KusionStack/terraform-provider-karpor"
+
+// traceContext propagates the W3C trace context of Karpor requests.
+var traceContext = propagation.TraceContext{}
+
+var (
+	// tracerProviderMu guards tracerProvider.
+	tracerProviderMu sync.Mutex
+	// tracerProvider exports the provider spans, nil until tracing is set up.
+	tracerProvider *sdktrace.TracerProvider
+)
+
+// tracer returns the tracer of the provider spans, a no-op tracer until
+// tracing is set up.
+func tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// setupTracing exports the provider spans over OTLP/HTTP to endpoint, or to
+// the endpoint of the OTEL_EXPORTER_OTLP_* environment variables if empty.
+// Tracing is set up once per provider process, later calls are ignored.
+func setupTracing(ctx context.Context, version string, endpoint string) error {
+	tracerProviderMu.Lock()
+	defer tracerProviderMu.Unlock()
+	if tracerProvider != nil {
+		return nil
+	}
+
+	var opts []otlptracehttp.Option
+	if endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(strings.TrimSuffix(endpoint, "/")+"/v1/traces"))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	res, err := sdkresource.Merge(sdkresource.Default(), sdkresource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName("terraform-provider-karpor"),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return err
+	}
+
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	return nil
+}
+
+// flushTracing exports the ended spans, as Terraform may stop the provider
+// process at any time after an operation.
+func flushTracing(ctx context.Context) {
+	tracerProviderMu.Lock()
+	tp := tracerProvider
+	tracerProviderMu.Unlock()
+	if tp != nil {
+		_ = tp.ForceFlush(ctx)
+	}
+}
+
+// NewTracedProviderServer wraps server with a span for every resource, data
+// source and ephemeral resource operation.
+func NewTracedProviderServer(server tfprotov6.ProviderServer) tfprotov6.ProviderServer {
+	return &tracedProviderServer{ProviderServer: server}
+}
+
+// tracedProviderServer is a tfprotov6.ProviderServer tracing the operations
+// of the wrapped server.
+type tracedProviderServer struct {
+	tfprotov6.ProviderServer
+}
+
+// ReadResource traces the read of a resource.
+func (s *tracedProviderServer) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	ctx, span := startOperationSpan(ctx, req.TypeName, "read")
+	resp, err := s.ProviderServer.ReadResource(ctx, req)
+	var diags []*tfprotov6.Diagnostic
+	if resp != nil {
+		diags = resp.Diagnostics
+	}
+	endOperationSpan(ctx, span, diags, err)
+	return resp, err
+}
+
+// ApplyResourceChange traces the create, update or delete of a resource.
+func (s *tracedProviderServer) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	operation := "update"
+	switch {
+	case isNullDynamicValue(req.PriorState):
+		operation = "create"
+	case isNullDynamicValue(req.PlannedState):
+		operation = "delete"
+	}
+	ctx, span := startOperationSpan(ctx, req.TypeName, operation)
+	resp, err := s.ProviderServer.ApplyResourceChange(ctx, req)
+	var diags []*tfprotov6.Diagnostic
+	if resp != nil {
+		diags = resp.Diagnostics
+	}
+	endOperationSpan(ctx, span, diags, err)
+	return resp, err
+}
+
+// ImportResourceState traces the import of a resource.
+func (s *tracedProviderServer) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	ctx, span := startOperationSpan(ctx, req.TypeName, "import")
+	resp, err := s.ProviderServer.ImportResourceState(ctx, req)
+	var diags []*tfprotov6.Diagnostic
+	if resp != nil {
+		diags = resp.Diagnostics
+	}
+	endOperationSpan(ctx, span, diags, err)
+	return resp, err
+}
+
+// ReadDataSource traces the read of a data source.
+func (s *tracedProviderServer) ReadDataSource(ctx context.Context, req *tfprotov6.ReadDataSourceRequest) (*tfprotov6.ReadDataSourceResponse, error) {
+	ctx, span := startOperationSpan(ctx, req.TypeName, "read")
+	resp, err := s.ProviderServer.ReadDataSource(ctx, req)
+	var diags []*tfprotov6.Diagnostic
+	if resp != nil {
+		diags = resp.Diagnostics
+	}
+	endOperationSpan(ctx, span, diags, err)
+	return resp, err
+}
+
+// OpenEphemeralResource traces the open of an ephemeral resource.
+func (s *tracedProviderServer) OpenEphemeralResource(ctx context.Context, req *tfprotov6.OpenEphemeralResourceRequest) (*tfprotov6.OpenEphemeralResourceResponse, error) {
+	ctx, span := startOperationSpan(ctx, req.TypeName, "open")
+	resp, err := s.ProviderServer.OpenEphemeralResource(ctx, req)
+	var diags []*tfprotov6.Diagnostic
+	if resp != nil {
+		diags = resp.Diagnostics
+	}
+	endOperationSpan(ctx, span, diags, err)
+	return resp, err
+}
+
+// startOperationSpan starts the span of an operation on a Terraform type,
+// named e.g. "karpor_role.create".
+func startOperationSpan(ctx context.Context, typeName string, operation string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, typeName+"."+operation, trace.WithAttributes(
+		attribute.String("terraform.type", typeName),
+		attribute.String("terraform.operation", operation),
+	))
+}
+
+// endOperationSpan records the error diagnostics of an operation on its
+// span, ends it and flushes it. Diagnostic details are left out as they may
+// hold response bodies.
+func endOperationSpan(ctx context.Context, span trace.Span, diags []*tfprotov6.Diagnostic, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	for _, d := range diags {
+		if d == nil || d.Severity != tfprotov6.DiagnosticSeverityError {
+			continue
+		}
+		span.AddEvent("diagnostic", trace.WithAttributes(attribute.String("summary", d.Summary)))
+		span.SetStatus(codes.Error, d.Summary)
+	}
+	span.End()
+	flushTracing(ctx)
+}
+
+// isNullDynamicValue reports whether v is a null value, as sent by Terraform
+// for the prior state of a create and the planned state of a delete.
+func isNullDynamicValue(v *tfprotov6.DynamicValue) bool {
+	if v == nil {
+		return true
+	}
+	if len(v.MsgPack) > 0 {
+		return bytes.Equal(v.MsgPack, []byte{0xc0})
+	}
+	return len(v.JSON) == 0 || string(bytes.TrimSpace(v.JSON)) == "null"
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans records the spans of the provider until the test ends.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestKarporClientTracing(t *testing.T) {
+	recorder := recordSpans(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"success": false, "message": "cluster demo not found"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewKarporClient(server.URL, "key", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, parent := tracer().Start(context.Background(), "karpor_cluster_registration.read")
+	_, err = client.GetCluster(ctx, "demo")
+	parent.End()
+	if !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected a request and an operation span, got %d", len(spans))
+	}
+	request := spans[0]
+	if request.Name() != http.MethodGet || request.SpanKind() != trace.SpanKindClient {
+		t.Errorf("unexpected request span %s of kind %s", request.Name(), request.SpanKind())
+	}
+	if request.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected the request span to be a child of the operation span")
+	}
+	if request.Status().Code != codes.Error || request.Status().Description != "Not Found" {
+		t.Errorf("unexpected request span status %v", request.Status())
+	}
+	expected := "00-" + request.SpanContext().TraceID().String() + "-" + request.SpanContext().SpanID().String() + "-01"
+	if traceparent != expected {
+		t.Errorf("expected traceparent %s, got %s", expected, traceparent)
+	}
+}
+
+// fakeProviderServer applies resource changes with the configured diagnostics.
+type fakeProviderServer struct {
+	tfprotov6.ProviderServer
+	diagnostics []*tfprotov6.Diagnostic
+}
+
+func (s *fakeProviderServer) ApplyResourceChange(_ context.Context, _ *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	return &tfprotov6.ApplyResourceChangeResponse{Diagnostics: s.diagnostics}, nil
+}
+
+func TestTracedProviderServer(t *testing.T) {
+	null := &tfprotov6.DynamicValue{MsgPack: []byte{0xc0}}
+	object := &tfprotov6.DynamicValue{MsgPack: []byte{0x81, 0xa4, 'n', 'a', 'm', 'e', 0xa4, 'd', 'e', 'm', 'o'}}
+	tests := map[string]struct {
+		prior, planned *tfprotov6.DynamicValue
+		diagnostics    []*tfprotov6.Diagnostic
+		expectedName   string
+		expectedStatus codes.Code
+	}{
+		"create": {
+			prior: null, planned: object,
+			expectedName: "karpor_role.create", expectedStatus: codes.Unset,
+		},
+		"update": {
+			prior: object, planned: object,
+			expectedName: "karpor_role.update", expectedStatus: codes.Unset,
+		},
+		"failed delete": {
+			prior: object, planned: null,
+			diagnostics: []*tfprotov6.Diagnostic{
+				{Severity: tfprotov6.DiagnosticSeverityWarning, Summary: "Deprecated"},
+				{Severity: tfprotov6.DiagnosticSeverityError, Summary: "Error Deleting Karpor Role"},
+			},
+			expectedName: "karpor_role.delete", expectedStatus: codes.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := recordSpans(t)
+			server := NewTracedProviderServer(&fakeProviderServer{diagnostics: test.diagnostics})
+			_, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+				TypeName:     "karpor_role",
+				PriorState:   test.prior,
+				PlannedState: test.planned,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("expected one span, got %d", len(spans))
+			}
+			if spans[0].Name() != test.expectedName || spans[0].Status().Code != test.expectedStatus {
+				t.Errorf("expected span %s with status %s, got %s with status %s",
+					test.expectedName, test.expectedStatus, spans[0].Name(), spans[0].Status().Code)
+			}
+		})
+	}
+}
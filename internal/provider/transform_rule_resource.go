@@ -0,0 +1,321 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template/parse"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// transformTypePatch renders a JSON merge patch applied to the object.
+	transformTypePatch = "patch"
+	// transformTypeReplace renders the object stored instead of the original.
+	transformTypeReplace = "replace"
+	// transformTypeRemove removes the fields matched by a JSONPath expression.
+	transformTypeRemove = "remove"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &TransformRuleResource{}
+	_ resource.ResourceWithConfigure      = &TransformRuleResource{}
+	_ resource.ResourceWithImportState    = &TransformRuleResource{}
+	_ resource.ResourceWithValidateConfig = &TransformRuleResource{}
+)
+
+// NewTransformRuleResource returns a new resource.Resource.
+func NewTransformRuleResource() resource.Resource {
+	return &TransformRuleResource{}
+}
+
+// TransformRuleResource is the resource implementation.
+type TransformRuleResource struct {
+	client *KarporClient
+}
+
+// TransformRuleResourceModel is the resource model.
+type TransformRuleResourceModel struct {
+	Name          types.String `tfsdk:"name"`
+	Type          types.String `tfsdk:"type"`
+	ValueTemplate types.String `tfsdk:"value_template"`
+	Id            types.String `tfsdk:"id"`
+}
+
+// Metadata returns the resource type name.
+func (r *TransformRuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_transform_rule"
+}
+
+// Schema returns the resource schema.
+func (r *TransformRuleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage a rule transforming objects before Karpor indexes them, e.g. to strip managed fields or secret data",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Unique name for the rule, referenced by `transform_rule` in `karpor_sync_resources_rule`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required: true,
+				Description: "Type of the transform, one of `patch` (`value_template` is a Go template rendering a JSON merge patch), " +
+					"`replace` (`value_template` is a Go template rendering the object to store) or " +
+					"`remove` (`value_template` is a JSONPath expression of the fields to remove)",
+				Validators: []validator.String{
+					stringvalidator.OneOf(transformTypePatch, transformTypeReplace, transformTypeRemove),
+				},
+			},
+			"value_template": schema.StringAttribute{
+				Required:    true,
+				Description: "Go template or JSONPath expression of the transform, depending on `type`",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks the transform expression against its type.
+func (r *TransformRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config TransformRuleResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values known only at apply time are validated by Karpor
+	if config.Type.IsNull() || config.Type.IsUnknown() || config.ValueTemplate.IsNull() || config.ValueTemplate.IsUnknown() {
+		return
+	}
+	if err := validateTransform(config.Type.ValueString(), config.ValueTemplate.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value_template"),
+			"Invalid Transform Expression",
+			fmt.Sprintf("The value_template of a %s transform is invalid: %s", config.Type.ValueString(), err),
+		)
+	}
+}
+
+// Create creates the resource.
+func (r *TransformRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan TransformRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, err := r.client.CreateTransformRule(ctx, plan.Name.ValueString(), plan.spec())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create transform rule", err.Error())
+		return
+	}
+	tflog.Info(ctx, "Created transform rule", map[string]interface{}{"name": rule.Metadata.Name})
+
+	plan.Id = types.StringValue(rule.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read reads the resource.
+func (r *TransformRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state TransformRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, err := r.client.GetTransformRule(ctx, state.Name.ValueString())
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "Transform rule removed outside of Terraform", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Karpor Transform Rule",
+			"Could not read Karpor transform rule "+state.Name.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(rule.Metadata.Name)
+	state.Type = types.StringValue(rule.Spec.Type)
+	state.ValueTemplate = types.StringValue(rule.Spec.ValueTemplate)
+	state.Id = types.StringValue(rule.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource.
+func (r *TransformRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan TransformRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, err := r.client.UpdateTransformRule(ctx, plan.Name.ValueString(), plan.spec())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update transform rule", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(rule.Metadata.UID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource.
+func (r *TransformRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state TransformRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteTransformRule(ctx, state.Name.ValueString())
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Karpor Transform Rule",
+			"Could not delete transform rule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+// ImportState imports the resource by name.
+func (r *TransformRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
+
+// Configure configures the resource.
+func (r *TransformRuleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*KarporClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data type",
+			fmt.Sprintf("Expected *KarporClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// spec converts the model into the rule spec sent to Karpor.
+func (m *TransformRuleResourceModel) spec() *TransformRuleSpec {
+	return &TransformRuleSpec{
+		Type:          m.Type.ValueString(),
+		ValueTemplate: m.ValueTemplate.ValueString(),
+	}
+}
+
+// validateTransform checks the syntax of a transform expression of the given type.
+func validateTransform(transformType string, value string) error {
+	switch transformType {
+	case transformTypePatch, transformTypeReplace:
+		return validateGoTemplate(value)
+	case transformTypeRemove:
+		return validateJSONPath(value)
+	default:
+		return fmt.Errorf("unknown transform type %q", transformType)
+	}
+}
+
+// validateGoTemplate checks the syntax of a Go template. Functions are not
+// checked as Karpor provides its own.
+func validateGoTemplate(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("template is empty")
+	}
+	tree := parse.New("value_template")
+	tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+	_, err := tree.Parse(value, "{{", "}}", map[string]*parse.Tree{})
+	return err
+}
+
+// validateJSONPath checks the syntax of a JSONPath expression such as
+// "$.metadata.managedFields" or "$.data['tls.key']".
+func validateJSONPath(value string) error {
+	if !strings.HasPrefix(value, "$") {
+		return fmt.Errorf("JSONPath expression must start with $")
+	}
+
+	runes := []rune(value)
+	for i := 1; i < len(runes); {
+		switch runes[i] {
+		case '.':
+			i++
+			if i < len(runes) && runes[i] == '.' {
+				i++
+			}
+			start := i
+			for i < len(runes) && runes[i] != '.' && runes[i] != '[' {
+				i++
+			}
+			if i == start {
+				return fmt.Errorf("missing field name at position %d", start+1)
+			}
+		case '[':
+			end, err := jsonPathBracketEnd(runes, i)
+			if err != nil {
+				return err
+			}
+			if end == i+1 {
+				return fmt.Errorf("empty brackets at position %d", i+1)
+			}
+			i = end + 1
+		default:
+			return fmt.Errorf("unexpected %q at position %d, expected . or [", runes[i], i+1)
+		}
+	}
+	return nil
+}
+
+// jsonPathBracketEnd returns the index of the bracket closing the one at
+// start, skipping quoted strings and nested brackets.
+func jsonPathBracketEnd(runes []rune, start int) (int, error) {
+	depth := 0
+	var quote rune
+	for i := start; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '[' || r == '(':
+			depth++
+		case r == ']' || r == ')':
+			depth--
+			if depth == 0 {
+				if r != ']' {
+					return 0, fmt.Errorf("unbalanced ) at position %d", i+1)
+				}
+				return i, nil
+			}
+		}
+	}
+	if quote != 0 {
+		return 0, fmt.Errorf("unterminated %c in brackets at position %d", quote, start+1)
+	}
+	return 0, fmt.Errorf("unclosed [ at position %d", start+1)
+}
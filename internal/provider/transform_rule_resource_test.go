@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccTransformRule(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Invalid expressions fail at plan time
+			{
+				Config: providerConfig + `
+				resource "karpor_transform_rule" "test" {
+					name           = "test-transform-rule"
+					type           = "patch"
+					value_template = "{{ .metadata.name "
+				}
+				`,
+				ExpectError: regexp.MustCompile(`Invalid Transform Expression`),
+			},
+			// Create and Read
+			{
+				Config: providerConfig + `
+				resource "karpor_transform_rule" "test" {
+					name           = "test-transform-rule"
+					type           = "remove"
+					value_template = "$.metadata.managedFields"
+				}
+
+				resource "karpor_sync_resources_rule" "test" {
+					name = "test-transform-sync-rule"
+					resources = [
+						{
+							api_version    = "v1"
+							kind           = "ConfigMap"
+							transform_rule = karpor_transform_rule.test.name
+						},
+					]
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"karpor_transform_rule.test",
+						tfjsonpath.New("id"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"karpor_sync_resources_rule.test",
+						tfjsonpath.New("resources").AtSliceIndex(0).AtMapKey("transform_rule"),
+						knownvalue.StringExact("test-transform-rule"),
+					),
+				},
+			},
+			// Import
+			{
+				ResourceName:                         "karpor_transform_rule.test",
+				ImportState:                          true,
+				ImportStateId:                        "test-transform-rule",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestValidateTransform(t *testing.T) {
+	valid := map[string]string{
+		"{{ toJson (omit .metadata \"managedFields\") }}":    transformTypePatch,
+		`{"data": {{ range $k, $v := .data }}{{ end }}null}`: transformTypeReplace,
+		"$.metadata.managedFields":                           transformTypeRemove,
+		"$.data['tls.key']":                                  transformTypeRemove,
+		"$.spec.containers[*].env[?(@.name=='TOKEN')]":       transformTypeRemove,
+		"$..annotations":                                     transformTypeRemove,
+	}
+	for value, transformType := range valid {
+		if err := validateTransform(transformType, value); err != nil {
+			t.Errorf("expected %s transform %q to be valid, got %s", transformType, value, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"{{ .metadata.name ":         transformTypePatch,
+		"{{ end }}":                  transformTypeReplace,
+		"":                           transformTypeReplace,
+		"metadata.managedFields":     transformTypeRemove,
+		"$.data['tls.key'":           transformTypeRemove,
+		"$.metadata.":                transformTypeRemove,
+		"$.spec[]":                   transformTypeRemove,
+		"$.data['unterminated]":      transformTypeRemove,
+		"$.spec.containers[0](name)": transformTypeRemove,
+	}
+	for value, transformType := range invalid {
+		if err := validateTransform(transformType, value); err == nil {
+			t.Errorf("expected %s transform %q to be invalid", transformType, value)
+		}
+	}
+}
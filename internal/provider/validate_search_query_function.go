@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &ValidateSearchQueryFunction{}
+
+// NewValidateSearchQueryFunction returns a new function.Function.
+func NewValidateSearchQueryFunction() function.Function {
+	return &ValidateSearchQueryFunction{}
+}
+
+// ValidateSearchQueryFunction is the validate_search_query function implementation.
+type ValidateSearchQueryFunction struct{}
+
+// Metadata returns the function name.
+func (f *ValidateSearchQueryFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_search_query"
+}
+
+// Definition returns the function signature.
+func (f *ValidateSearchQueryFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Validate a Karpor search query",
+		Description: "Returns the Karpor SQL query unchanged, failing with the position of the first syntax error if it is invalid",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "query",
+				Description: "Karpor SQL query",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run runs the function.
+func (f *ValidateSearchQueryFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var query string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &query))
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateSearchQuery(query); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, query))
+}
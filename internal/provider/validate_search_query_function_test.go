@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccValidateSearchQueryFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::validate_search_query("select * from resources where kind = 'Pod'")
+				}
+				`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact("select * from resources where kind = 'Pod'")),
+				},
+			},
+			{
+				Config: providerConfig + `
+				output "test" {
+					value = provider::karpor::validate_search_query("select * from resources where kind = Pod")
+				}
+				`,
+				ExpectError: regexp.MustCompile(`syntax error at position 38`),
+			},
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// verifyCredentials makes one authenticated call to Karpor and reports why
+// it failed as a diagnostic on the provider attribute to fix.
+func verifyCredentials(ctx context.Context, client *KarporClient) diag.Diagnostics {
+	var diags diag.Diagnostics
+	_, err := client.GetCurrentIdentity(ctx)
+	if err == nil {
+		return diags
+	}
+
+	var statusErr *StatusError
+	switch {
+	case isTLSError(err):
+		diags.AddAttributeError(
+			path.Root("api_endpoint"),
+			"Karpor TLS Verification Failed",
+			"The certificate of the Karpor API endpoint could not be verified. "+
+				"Make sure the endpoint uses HTTPS with a certificate trusted by this machine, "+
+				"or set skip_tls_verify for testing environments.\n\nError: "+err.Error(),
+		)
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized:
+		diags.AddAttributeError(
+			path.Root("api_key"),
+			"Invalid Karpor API Key",
+			"Karpor rejected the API key. Make sure api_key or the KARPOR_API_KEY environment variable "+
+				"holds a valid token that has not expired or been revoked.",
+		)
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden:
+		diags.AddAttributeError(
+			path.Root("api_key"),
+			"Insufficient Karpor Permissions",
+			"The API key is valid but is not permitted to read its own identity. "+
+				"Grant its subject a karpor_role with a karpor_role_binding.",
+		)
+	case isNetworkError(err):
+		diags.AddAttributeError(
+			path.Root("api_endpoint"),
+			"Karpor API Endpoint Unreachable",
+			"The Karpor API endpoint could not be reached. Make sure api_endpoint or the KARPOR_API_ENDPOINT "+
+				"environment variable is the address of the Karpor server and that it is reachable from this machine.\n\nError: "+err.Error(),
+		)
+	default:
+		diags.AddError(
+			"Failed to Verify Karpor Credentials",
+			"An unexpected error occurred when verifying the Karpor credentials. "+
+				"Set verify_credentials to false to skip the verification.\n\nError: "+err.Error(),
+		)
+	}
+	return diags
+}
+
+// isTLSError reports whether err is a failed TLS handshake or certificate
+// verification.
+func isTLSError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &certErr) || errors.As(err, &recordErr) || errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &invalidErr)
+}
+
+// isNetworkError reports whether err is a failure to resolve or connect to
+// the endpoint, including timeouts.
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) || (errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
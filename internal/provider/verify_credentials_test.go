@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestVerifyCredentials(t *testing.T) {
+	identity := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer valid":
+			_, _ = w.Write([]byte(`{"success": true, "data": {"name": "ci", "kind": "APIToken"}}`))
+		case "Bearer restricted":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	plainServer := httptest.NewServer(identity)
+	defer plainServer.Close()
+	tlsServer := httptest.NewTLSServer(identity)
+	defer tlsServer.Close()
+	closedServer := httptest.NewServer(identity)
+	closedServer.Close()
+
+	tests := []struct {
+		name          string
+		endpoint      string
+		key           string
+		skipTLSVerify bool
+		summary       string
+		attribute     path.Path
+	}{
+		{name: "valid", endpoint: plainServer.URL, key: "valid"},
+		{name: "valid with skipped TLS verification", endpoint: tlsServer.URL, key: "valid", skipTLSVerify: true},
+		{name: "bad token", endpoint: plainServer.URL, key: "expired", summary: "Invalid Karpor API Key", attribute: path.Root("api_key")},
+		{name: "insufficient permissions", endpoint: plainServer.URL, key: "restricted", summary: "Insufficient Karpor Permissions", attribute: path.Root("api_key")},
+		{name: "untrusted certificate", endpoint: tlsServer.URL, key: "valid", summary: "Karpor TLS Verification Failed", attribute: path.Root("api_endpoint")},
+		{name: "unreachable endpoint", endpoint: closedServer.URL, key: "valid", summary: "Karpor API Endpoint Unreachable", attribute: path.Root("api_endpoint")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewKarporClient(tt.endpoint, tt.key, tt.skipTLSVerify)
+			if err != nil {
+				t.Fatal(err)
+			}
+			diags := verifyCredentials(context.Background(), client)
+			if tt.summary == "" {
+				if diags.HasError() {
+					t.Fatalf("expected no error, got %v", diags)
+				}
+				return
+			}
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("expected one error, got %v", diags)
+			}
+			if got := diags.Errors()[0].Summary(); got != tt.summary {
+				t.Errorf("expected %q, got %q: %s", tt.summary, got, diags.Errors()[0].Detail())
+			}
+			withPath, ok := diags.Errors()[0].(interface{ Path() path.Path })
+			if !ok || !withPath.Path().Equal(tt.attribute) {
+				t.Errorf("expected the error on %s, got %v", tt.attribute, diags.Errors()[0])
+			}
+		})
+	}
+}
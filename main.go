@@ -1,12 +1,13 @@
 package main
 
 import (
-	"context"
 	"flag"
 	"log"
 
 	"github.com/KusionStack/terraform-provider-karpor/internal/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 )
 
 var (
@@ -23,13 +24,17 @@ func main() {
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers")
 	flag.Parse()
 
-	err := providerserver.Serve(
-		context.Background(),
-		provider.New(version),
-		providerserver.ServeOpts{
-			Address: "registry.terraform.io/KusionStack/karpor",
-			Debug:   debug,
+	var serveOpts []tf6server.ServeOpt
+	if debug {
+		serveOpts = append(serveOpts, tf6server.WithManagedDebug())
+	}
+
+	err := tf6server.Serve(
+		"registry.terraform.io/KusionStack/karpor",
+		func() tfprotov6.ProviderServer {
+			return provider.NewTracedProviderServer(providerserver.NewProtocol6(provider.New(version)())())
 		},
+		serveOpts...,
 	)
 
 	if err != nil {